@@ -445,7 +445,7 @@ func (s *stack) Run(ctx context.Context, input *StackInput) error {
 				}
 				for _, link := range receiver.Links {
 					if cloudflareBindings[link] != "" && receiver.Cloudflare != nil {
-						links[link] = literal{value: `import("@cloudflare/workers-types").` + cloudflareBindings[link]}
+						links[link] = Literal(`import("@cloudflare/workers-types").` + cloudflareBindings[link])
 						continue
 					}
 					links[link] = complete.Links[link]
@@ -453,13 +453,10 @@ func (s *stack) Run(ctx context.Context, input *StackInput) error {
 			}
 
 			for path, links := range types {
-				typesFile, _ := os.Create(filepath.Join(path, "sst-env.d.ts"))
-				defer typesFile.Close()
-				typesFile.WriteString(`import "sst"` + "\n")
-				typesFile.WriteString(`declare module "sst" {` + "\n")
-				typesFile.WriteString("  export interface Resource " + inferTypes(links, "  ") + "\n")
-				typesFile.WriteString("}" + "\n")
-				typesFile.WriteString("export {}")
+				typesPath := filepath.Join(path, "sst-env.d.ts")
+				if _, err := WriteTypesFile(typesPath, links); err != nil {
+					slog.Error("failed to write types", "path", typesPath, "error", err)
+				}
 			}
 			provider.PutLinks(s.project.home, s.project.app.Name, s.project.app.Stage, complete.Links)
 		}
@@ -1,45 +1,1578 @@
 package project
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 )
 
-type literal struct {
+// Literal is a raw TypeScript type that is rendered verbatim instead of
+// being inferred.
+type Literal string
+
+// Union renders values as a union of string literals.
+func Union(values []string) Literal {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = quoteString(value)
+	}
+	return Literal(strings.Join(quoted, " | "))
+}
+
+// union holds every value seen for the same position while merging; it
+// renders as the TypeScript union of their types.
+type union []interface{}
+
+// objects are merged into a single shape once they are rendered. Merging
+// lazily, one level at a time, lets cycles be caught while rendering.
+type objects []map[string]interface{}
+
+type dynamic struct {
+	value map[string]interface{}
+}
+
+// Dynamic renders value as an index signature over the merged type of its
+// values instead of listing its keys, for maps like tags or environment
+// variables whose keys are not known ahead of time.
+func Dynamic(value map[string]interface{}) interface{} {
+	return dynamic{value: value}
+}
+
+type optional struct {
+	value interface{}
+}
+
+// Optional marks value as a property that may be absent. The type of value
+// is still inferred as usual.
+func Optional(value interface{}) interface{} {
+	return optional{value: value}
+}
+
+type tuple []interface{}
+
+// Tuple renders as a TypeScript tuple with the type of each value at its
+// position. Trailing values wrapped in Optional become optional elements.
+func Tuple(values ...interface{}) interface{} {
+	return tuple(values)
+}
+
+type constant struct {
+	value interface{}
+}
+
+// Const renders a string, number or boolean as its literal type, like
+// "production" or 8080, instead of widening it.
+func Const(value interface{}) interface{} {
+	return constant{value: value}
+}
+
+type enum []interface{}
+
+// Enum renders as the union of values, which must be strings or numbers.
+// InferInterfaces declares it as a named type when HoistEnums is set.
+func Enum(values ...interface{}) interface{} {
+	return enum(values)
+}
+
+type secret struct {
 	value string
 }
 
+// Secret marks value as sensitive. It is typed as string without ever being
+// rendered and its key path is listed by InferSecrets.
+func Secret(value string) interface{} {
+	return secret{value: value}
+}
+
+type document struct {
+	raw []byte
+}
+
+// JSON renders the type of the JSON document in raw, which may be a string,
+// []byte or json.RawMessage, instead of typing it as a string. Invalid
+// documents are typed as string when inferring leniently.
+func JSON[T string | []byte | json.RawMessage](raw T) interface{} {
+	return document{raw: []byte(raw)}
+}
+
+type TypeOptions struct {
+	// Less orders the keys of every object block. Keys are sorted
+	// alphabetically when it is nil.
+	Less func(a, b string) bool
+	// KeyPriority pins the listed keys to the top of every object block,
+	// in the given order, ahead of Less. Keys of an OrderedMap keep their
+	// order.
+	KeyPriority []string
+	// Lenient renders unsupported values as unknown and reports them
+	// through Warn instead of failing.
+	Lenient bool
+	Warn    func(warning string)
+	// FallbackType decides what becomes of values that cannot be inferred
+	// regardless of Lenient: "unknown" and "any" render them as that type
+	// and warn, "error" always fails.
+	FallbackType string
+	// Overrides forces the type rendered at dotted key paths, like
+	// "Legacy.config" or "Rules[].action", instead of inferring it.
+	Overrides map[string]string
+	// NilType is rendered for nil values, which are marked optional.
+	// Defaults to unknown.
+	NilType string
+
+	// MaxDepth limits how deep objects are inferred, counting the root
+	// object as the first level. Deeper objects are rendered as
+	// DepthFallback, defaulting to any, and reported through Warn. Zero
+	// means unlimited.
+	MaxDepth      int
+	DepthFallback string
+
+	// IntegerType and FloatType replace number as the type of integers and
+	// floats, for example with branded types like Integer.
+	IntegerType string
+	FloatType   string
+
+	// Indent is written once per nesting level. Defaults to two spaces.
+	Indent string
+	// FieldTerminator is appended to every property, typically ";" or ",".
+	FieldTerminator string
+	// TerminateBlocks also appends FieldTerminator to properties whose
+	// type spans multiple lines.
+	TerminateBlocks bool
+	// TrailingNewline ends the rendered output with a newline.
+	TrailingNewline bool
+	// PreferLiterals renders every string, number and boolean as its
+	// literal type, as if wrapped in Const.
+	PreferLiterals bool
+	// Readonly marks every rendered property readonly.
+	Readonly bool
+	// Outputs wraps the type of every property that is not an object in
+	// Pulumi's Output<T>. InferInterfaces also imports Output.
+	Outputs bool
+	// OutputObjects wraps whole objects in Output<T> instead of the
+	// properties inside them.
+	OutputObjects bool
+
+	// Discriminators lists the keys whose string values are rendered as
+	// string literals. Defaults to "type"; set an empty slice to disable.
+	Discriminators []string
+	// ShallowDiscriminators only applies Discriminators to the keys of the
+	// root object and of the objects directly inside it.
+	ShallowDiscriminators bool
+
+	// RootName names the root interface rendered by InferInterfaces.
+	// Defaults to Resource.
+	RootName string
+	// HoistNested makes InferInterfaces hoist objects below the top level
+	// into interfaces of their own as well.
+	HoistNested bool
+	// HoistEnums makes InferInterfaces declare every Enum as a named type.
+	HoistEnums bool
+
+	// Namespace groups the top-level keys rendered by InferNamespaces,
+	// taking precedence over Namespaces. Keys mapped to an empty string are
+	// placed in DefaultNamespace, which defaults to "other".
+	Namespace        func(key string) string
+	Namespaces       map[string]string
+	DefaultNamespace string
+
+	// Types overrides the type rendered for values of specific Go types,
+	// for example mapping time.Time to Date instead of string.
+	Types map[reflect.Type]string
+
+	// RejectUndiscriminated makes InferUnion fail on inputs without a
+	// string discriminator instead of collecting them into a last branch.
+	RejectUndiscriminated bool
+
+	// RejectConflicts makes MergeTypes fail when the same key has different
+	// types instead of typing it as a union of both.
+	RejectConflicts bool
+
+	// Descriptions documents properties by their dotted key path, such as
+	// "MyBucket.name". Each description is rendered as a JSDoc comment above
+	// its property and one starting with "Deprecated:" is tagged
+	// @deprecated. Paths that match no property are reported through Warn.
+	Descriptions map[string]string
+
+	// CheckKeyCollisions reports keys of the same object that differ only
+	// by case through Warn. CheckIdentifierCollisions also reports keys
+	// that become the same identifier once sanitized, like my-api and
+	// my_api. RejectKeyCollisions reports them as KeyCollision errors
+	// instead.
+	CheckKeyCollisions        bool
+	CheckIdentifierCollisions bool
+	RejectKeyCollisions       bool
+
+	// ReportUnexpectedKeys makes Validate report payload keys that the
+	// shape does not declare.
+	ReportUnexpectedKeys bool
+
+	// Augmentations are merged into the input before inferring it, adding
+	// hand-written properties to the generated ones. Objects present in both
+	// are merged key by key; any other key present in both keeps its
+	// generated value unless RejectAugmentationConflicts makes it an error.
+	// See ReadAugmentations.
+	Augmentations               map[string]interface{}
+	RejectAugmentationConflicts bool
+
+	// WarningsAsErrors reports every warning as an error instead of passing
+	// it to Warn.
+	WarningsAsErrors bool
+
+	// StrictSecrets makes InferSecrets fail when the value of a Secret
+	// appears in the rendered output anyway, for example through a Literal.
+	StrictSecrets bool
+}
+
+// TypeFirst sorts keys alphabetically but always places "type" first.
+func TypeFirst(a, b string) bool {
+	if a == "type" || b == "type" {
+		return a == "type" && b != "type"
+	}
+	return a < b
+}
+
+// InferTypes renders the TypeScript object type describing input. Every
+// value that cannot be represented is reported by its key path; with
+// Lenient set those values become unknown instead.
+func InferTypes(input map[string]interface{}, opts TypeOptions) (string, error) {
+	var builder strings.Builder
+	err := Render(&builder, input, opts)
+	return builder.String(), err
+}
+
+// Infer builds the type tree that InferTypes renders.
+func Infer(input map[string]interface{}, opts TypeOptions) (Type, error) {
+	g := &typeGenerator{
+		opts:         opts,
+		discriminate: true,
+	}
+	return g.root(input)
+}
+
+func (g *typeGenerator) root(input map[string]interface{}) (Type, error) {
+	result := g.object(g.augment(input, g.opts.Augmentations, ""), "")
+	g.unusedDescriptions()
+	return result, errors.Join(g.errs...)
+}
+
+// InferSecrets renders input like InferTypes and also returns the sorted
+// key paths of every Secret in it.
+func InferSecrets(input map[string]interface{}, opts TypeOptions) (string, []string, error) {
+	g := &typeGenerator{
+		opts:         opts,
+		discriminate: true,
+	}
+	t := g.object(g.augment(input, opts.Augmentations, ""), "")
+	g.unusedDescriptions()
+	result := RenderType(t, opts)
+	paths := make([]string, 0, len(g.secrets))
+	for path := range g.secrets {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	if opts.StrictSecrets {
+		tokens := map[string]bool{}
+		for _, literal := range literalValues(t, nil) {
+			for _, token := range literalTokens(literal) {
+				tokens[token] = true
+			}
+		}
+		for _, path := range paths {
+			for _, value := range g.secrets[path] {
+				if value != "" && tokens[value] {
+					g.report(path, fmt.Errorf("%s: secret value is rendered in the output", path), false)
+					break
+				}
+			}
+		}
+	}
+	return result, paths, errors.Join(g.errs...)
+}
+
+// literalValues collects every fragment of t rendered from a value rather
+// than inferred, which is where a secret could leak.
+func literalValues(t Type, values []string) []string {
+	switch t := t.(type) {
+	case LiteralType:
+		values = append(values, t.Value)
+	case ObjectType:
+		for _, field := range t.Fields {
+			values = literalValues(field.Type, values)
+		}
+	case ArrayType:
+		values = literalValues(t.Element, values)
+	case TupleType:
+		for _, element := range t.Elements {
+			values = literalValues(element.Type, values)
+		}
+	case UnionType:
+		for _, member := range t.Members {
+			values = literalValues(member, values)
+		}
+	case RecordType:
+		values = literalValues(t.Value, values)
+	}
+	return values
+}
+
+// literalTokens returns the contents of every quoted string in literal,
+// along with literal itself.
+func literalTokens(literal string) []string {
+	tokens := []string{strings.TrimSpace(literal)}
+	for i := 0; i < len(literal); i++ {
+		quote := literal[i]
+		if quote != '"' && quote != '\'' && quote != '`' {
+			continue
+		}
+		end := i + 1
+		for end < len(literal) && literal[end] != quote {
+			if literal[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(literal) {
+			break
+		}
+		token := literal[i+1 : end]
+		if unquoted, err := strconv.Unquote(`"` + token + `"`); err == nil {
+			token = unquoted
+		}
+		tokens = append(tokens, token)
+		i = end
+	}
+	return tokens
+}
+
+// Render streams the output of InferTypes to w. Rendering stops as soon as
+// a write fails and that error is returned.
+func Render(w io.Writer, input map[string]interface{}, opts ...TypeOptions) error {
+	var options TypeOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	t, inferErr := Infer(input, options)
+	if err := streamType(w, t, options, ""); err != nil {
+		return err
+	}
+	return inferErr
+}
+
+// InferInterfaces renders every top-level object in input as an exported
+// interface and a root interface that references them by name.
+func InferInterfaces(input map[string]interface{}, opts TypeOptions) (string, error) {
+	output, g := renderInterfaces(input, opts)
+	return output, errors.Join(g.errs...)
+}
+
+// InferInterfacesResult renders input like InferInterfaces, also reporting
+// the identifier every hoisted key path was declared as.
+func InferInterfacesResult(input map[string]interface{}, opts TypeOptions) Result {
+	output, g := renderInterfaces(input, opts)
+	return Result{
+		Output:      output,
+		Diagnostics: g.diagnostics,
+		Identifiers: g.identifiers,
+	}
+}
+
+func renderInterfaces(input map[string]interface{}, opts TypeOptions) (string, *typeGenerator) {
+	g := newInterfaceGenerator(opts)
+	declarations := g.interfaceDeclarations(input)
+
+	var builder strings.Builder
+	w := &typeWriter{w: &builder, opts: opts}
+	if opts.Outputs {
+		w.string(`import { Output } from "@pulumi/pulumi"` + "\n\n")
+	}
+	for i, declaration := range declarations {
+		if i > 0 {
+			w.string("\n\n")
+			// Hoisted interfaces are only referenced from within an Output.
+			w.output = opts.OutputObjects
+		}
+		if declaration.alias != nil {
+			w.string("export type " + declaration.name + " = ")
+			w.write(declaration.alias, "")
+			continue
+		}
+		w.string("export interface " + declaration.name + " ")
+		w.object(declaration.object, "")
+	}
+	if opts.TrailingNewline {
+		w.string("\n")
+	}
+	return builder.String(), g
+}
+
+func newInterfaceGenerator(opts TypeOptions) *typeGenerator {
+	return &typeGenerator{
+		opts:         opts,
+		discriminate: true,
+		names:        map[string]bool{},
+		interfaces:   map[uintptr]string{},
+		identifiers:  map[string]string{},
+	}
+}
+
+// interfaceDeclarations infers input as the root interface, followed by
+// every interface hoisted out of it.
+func (g *typeGenerator) interfaceDeclarations(input map[string]interface{}) []declaration {
+	root := g.opts.RootName
+	if root == "" {
+		root = "Resource"
+	}
+	input = g.augment(input, g.opts.Augmentations, "")
+	g.names[root] = true
+	g.interfaces[reflect.ValueOf(input).Pointer()] = root
+	g.scope = root
+	object := g.object(input, "")
+	g.unusedDescriptions()
+	return append([]declaration{{name: root, object: object}}, g.declarations...)
+}
+
+// InferUnion renders inputs as a union with one branch per value of their
+// discriminator key. Inputs sharing a value are merged into one branch.
+func InferUnion(inputs []map[string]interface{}, discriminator string, opts ...TypeOptions) (string, error) {
+	g := &typeGenerator{
+		discriminate: true,
+	}
+	if len(opts) > 0 {
+		g.opts = opts[0]
+	}
+	if !g.isDiscriminator(discriminator) {
+		g.opts.Discriminators = append([]string{discriminator}, g.discriminators()...)
+	}
+	groups := map[string]objects{}
+	var fallback objects
+	for i, input := range inputs {
+		value, ok := input[discriminator].(string)
+		if ok {
+			groups[value] = append(groups[value], input)
+			continue
+		}
+		if g.opts.RejectUndiscriminated {
+			path := "[" + strconv.Itoa(i) + "]"
+			g.report(path, fmt.Errorf("%s: missing string discriminator %q", path, discriminator), false)
+			continue
+		}
+		fallback = append(fallback, input)
+	}
+	values := make([]string, 0, len(groups))
+	for value := range groups {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	branches := make([]objects, 0, len(groups)+1)
+	for _, value := range values {
+		branches = append(branches, groups[value])
+	}
+	if len(fallback) > 0 {
+		branches = append(branches, fallback)
+	}
+	var result UnionType
+	for _, branch := range branches {
+		if len(branch) == 1 {
+			result.Members = append(result.Members, g.value(branch[0], ""))
+			continue
+		}
+		result.Members = append(result.Members, g.value(branch, ""))
+	}
+	g.unusedDescriptions()
+	return RenderType(result, g.opts), errors.Join(g.errs...)
+}
+
 func inferTypes(input map[string]interface{}, indentArgs ...string) string {
+	return inferTypesWithOptions(input, TypeOptions{}, indentArgs...)
+}
+
+func inferTypesWithOptions(input map[string]interface{}, opts TypeOptions, indentArgs ...string) string {
 	indent := ""
 	if len(indentArgs) > 0 {
 		indent = indentArgs[0]
 	}
+	g := &typeGenerator{
+		opts:         opts,
+		legacy:       true,
+		discriminate: true,
+		plainRoot:    len(indentArgs) == 0,
+	}
 	var builder strings.Builder
-	builder.WriteString("{")
-	builder.WriteString("\n")
-	for key, value := range input {
-		builder.WriteString(indent + "  " + key + ": ")
-		if key == "type" && len(indentArgs) == 1 {
-			builder.WriteString("\"")
-			builder.WriteString(value.(string))
-			builder.WriteString("\"")
+	w := &typeWriter{w: &builder, opts: opts}
+	w.object(g.object(input, ""), indent)
+	return builder.String()
+}
+
+type declaration struct {
+	name   string
+	object ObjectType
+	// alias is set for type aliases instead of interfaces.
+	alias Type
+}
+
+type typeGenerator struct {
+	opts TypeOptions
+	// legacy skips unsupported values, matching the output inferTypes has
+	// always produced.
+	legacy       bool
+	discriminate bool
+	// plainRoot leaves the discriminators of the root object untyped, as
+	// inferTypes always has when called without an indent.
+	plainRoot bool
+	errs      []error
+	// diagnostics holds every error and warning in the order they were
+	// found.
+	diagnostics []Diagnostic
+
+	// names is set when objects are hoisted into named interfaces.
+	names        map[string]bool
+	declarations []declaration
+	interfaces   map[uintptr]string
+	enums        map[string]string
+	// identifiers maps the key path of every hoisted object and enum to
+	// the name it was declared as.
+	identifiers map[string]string
+	scope       string
+	depth       int
+
+	// visiting holds the path of every object currently being inferred.
+	visiting map[uintptr]string
+	// described holds every path in Descriptions that matched a property.
+	described map[string]bool
+	// order holds the key order of every OrderedMap by its values.
+	order map[uintptr][]string
+	// structs holds the object of every struct reached through a pointer.
+	structs map[uintptr]map[string]interface{}
+	// zeros holds every type whose zero value is currently being inferred.
+	zeros map[reflect.Type]bool
+	// secrets maps the path of every Secret to its values.
+	secrets map[string][]string
+	// less caches the key order built from KeyPriority and Less.
+	less func(a, b string) bool
+}
+
+// object infers the type of input. sources are the objects input was merged
+// from, if any.
+func (g *typeGenerator) object(input map[string]interface{}, path string, sources ...map[string]interface{}) ObjectType {
+	if g.visiting == nil {
+		g.visiting = map[uintptr]string{}
+	}
+	pointer, marked := g.mark(input, path)
+	for _, source := range sources {
+		if pointer, ok := g.mark(source, path); ok {
+			defer delete(g.visiting, pointer)
+		}
+	}
+	g.depth++
+	result := ObjectType{Fields: make([]Field, 0, len(input))}
+	keys := g.keys(input, sources)
+	g.collisions(keys, path)
+	for _, key := range keys {
+		value, isOptional := unwrapOptional(input[key])
+		value = g.resolve(value)
+		if m, ok := value.(*OrderedMap); ok {
+			value = g.ordered(m)
+		}
+		field := Field{
+			Key:      key,
+			Optional: isOptional || isNil(value),
+		}
+		if len(g.opts.Overrides) > 0 {
+			if override, ok := g.override(joinPath(path, key)); ok {
+				field.Type = override
+				result.Fields = append(result.Fields, field)
+				continue
+			}
+		}
+		// Most values are plain primitives that need no key path.
+		if primitive, ok := g.primitive(key, value); ok {
+			field.Type = primitive
+			result.Fields = append(result.Fields, field)
+			continue
+		}
+		fieldPath := joinPath(path, key)
+		field.Description = g.describe(fieldPath)
+		if discriminant, ok := g.discriminant(key, value, fieldPath); ok {
+			field.Type = discriminant
+		} else if nested, ok := value.(map[string]interface{}); ok && g.hoist() {
+			if fallback, ok := g.truncate(fieldPath); ok {
+				field.Type = fallback
+			} else if cycle, ok := g.cycle(fieldPath, nested); ok {
+				field.Type = cycle
+			} else {
+				field.Type = g.declare(key, nested, fieldPath)
+			}
 		} else {
-			switch v := value.(type) {
-			case literal:
-				builder.WriteString(v.value)
-			case string:
-				builder.WriteString("string")
-			case int:
-				builder.WriteString("number")
-			case float64:
-				builder.WriteString("number")
-			case float32:
-				builder.WriteString("number")
-			case map[string]interface{}:
-				builder.WriteString(inferTypes(value.(map[string]interface{}), indent+"  "))
-			}
-		}
-		builder.WriteString("\n")
-	}
-	builder.WriteString(indent + "}")
+			field.Type = g.value(value, fieldPath)
+		}
+		result.Fields = append(result.Fields, field)
+	}
+	g.depth--
+	if marked {
+		delete(g.visiting, pointer)
+	}
+	return result
+}
+
+// mark records that input is being inferred at path, unless it already is.
+func (g *typeGenerator) mark(input map[string]interface{}, path string) (uintptr, bool) {
+	pointer := reflect.ValueOf(input).Pointer()
+	if _, ok := g.visiting[pointer]; ok || pointer == 0 {
+		return 0, false
+	}
+	g.visiting[pointer] = path
+	return pointer, true
+}
+
+// primitive types the value of key when it is a plain string, boolean or
+// number that no option affects.
+func (g *typeGenerator) primitive(key string, value interface{}) (Type, bool) {
+	if g.opts.Types != nil || g.opts.PreferLiterals || len(g.opts.Descriptions) > 0 || g.isDiscriminator(key) {
+		return nil, false
+	}
+	switch value.(type) {
+	case string:
+		return PrimitiveType("string"), true
+	case bool:
+		return PrimitiveType("boolean"), true
+	case float64, float32:
+		return NumberType{}, true
+	case int, int64, int32, uint, uint64, uint32:
+		return NumberType{Integer: true}, true
+	}
+	return nil, false
+}
+
+// discriminant types discriminator keys as a string literal, or a union of
+// literals when several objects were merged.
+func (g *typeGenerator) discriminant(key string, value interface{}, path string) (Type, bool) {
+	if !g.discriminate || isNil(value) || !g.isDiscriminator(key) {
+		return nil, false
+	}
+	if g.plainRoot && g.depth == 1 {
+		return nil, false
+	}
+	if g.opts.ShallowDiscriminators && g.depth > 2 {
+		return nil, false
+	}
+	if c, ok := value.(constant); ok {
+		value = c.value
+	}
+	if str, ok := value.(string); ok {
+		return LiteralType{Value: quoteString(str), Constant: true}, true
+	}
+	members, ok := value.(union)
+	if !ok {
+		members = union{value}
+	}
+	var result UnionType
+	seen := map[string]bool{}
+	for _, member := range members {
+		str, ok := member.(string)
+		if !ok {
+			g.report(path, fmt.Errorf("%s: discriminator is %T, not a string", path, member), true)
+			return PrimitiveType("string"), true
+		}
+		if seen[str] {
+			continue
+		}
+		seen[str] = true
+		result.Members = append(result.Members, LiteralType{Value: quoteString(str), Constant: true})
+	}
+	if len(result.Members) == 1 {
+		return result.Members[0], true
+	}
+	return result, true
+}
+
+func (g *typeGenerator) describe(path string) string {
+	description, ok := g.opts.Descriptions[path]
+	if !ok {
+		return ""
+	}
+	if g.described == nil {
+		g.described = map[string]bool{}
+	}
+	g.described[path] = true
+	return description
+}
+
+func (g *typeGenerator) unusedDescriptions() {
+	var unused []string
+	for path := range g.opts.Descriptions {
+		if !g.described[path] {
+			unused = append(unused, path)
+		}
+	}
+	sort.Strings(unused)
+	for _, path := range unused {
+		g.report(path, fmt.Errorf("%s: description matches no property", path), true)
+	}
+}
+
+func (g *typeGenerator) discriminators() []string {
+	if g.opts.Discriminators == nil {
+		return []string{"type"}
+	}
+	return g.opts.Discriminators
+}
+
+func (g *typeGenerator) isDiscriminator(key string) bool {
+	for _, discriminator := range g.discriminators() {
+		if key == discriminator {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *typeGenerator) hoist() bool {
+	return g.names != nil && (g.depth == 1 || g.opts.HoistNested)
+}
+
+// declare hoists input into an exported interface and returns a reference
+// to it. Nested objects are named after their parent so a hoisted
+// MyQueue.dlq becomes MyQueueDlq.
+func (g *typeGenerator) declare(key string, input map[string]interface{}, path string) Type {
+	name := SanitizeIdentifier(key)
+	if g.depth > 1 {
+		name = g.scope + name
+	}
+	name = g.uniqueName(name)
+	g.identifiers[path] = name
+	g.interfaces[reflect.ValueOf(input).Pointer()] = name
+	index := len(g.declarations)
+	g.declarations = append(g.declarations, declaration{name: name})
+	scope := g.scope
+	g.scope = name
+	object := g.object(input, path)
+	g.scope = scope
+	g.declarations[index].object = object
+	return ReferenceType{Name: name}
+}
+
+// cycle reports whether any of inputs is already being inferred. A cycle
+// back to a named interface becomes a reference to it; any other cycle is
+// an error.
+func (g *typeGenerator) cycle(path string, inputs ...map[string]interface{}) (Type, bool) {
+	for _, input := range inputs {
+		pointer := reflect.ValueOf(input).Pointer()
+		target, ok := g.visiting[pointer]
+		if !ok || pointer == 0 {
+			continue
+		}
+		if name, ok := g.interfaces[pointer]; ok {
+			return ReferenceType{Name: name}, true
+		}
+		if target == "" {
+			target = "the root object"
+		}
+		return g.fail(path, fmt.Errorf("%s: cyclic reference to %s", path, target)), true
+	}
+	return nil, false
+}
+
+// KeyCollision describes two keys of the object at Path that only differ by
+// case or by characters dropped from identifiers.
+type KeyCollision struct {
+	Path  string
+	Key   string
+	Other string
+}
+
+func (c *KeyCollision) Error() string {
+	path := c.Path
+	if path == "" {
+		path = "the root object"
+	}
+	return fmt.Sprintf("%s: keys %q and %q collide", path, c.Key, c.Other)
+}
+
+func (g *typeGenerator) collisions(keys []string, path string) {
+	if !g.opts.CheckKeyCollisions && !g.opts.CheckIdentifierCollisions && !g.opts.RejectKeyCollisions {
+		return
+	}
+	seen := map[string]string{}
+	for _, key := range keys {
+		normalized := key
+		if g.opts.CheckIdentifierCollisions {
+			normalized = SanitizeIdentifier(key)
+		}
+		normalized = strings.ToLower(normalized)
+		other, ok := seen[normalized]
+		if !ok {
+			seen[normalized] = key
+			continue
+		}
+		collision := &KeyCollision{Path: path, Key: other, Other: key}
+		g.report(path, collision, !g.opts.RejectKeyCollisions)
+	}
+}
+
+// truncate returns the fallback type for an object at path when it would
+// nest deeper than MaxDepth.
+func (g *typeGenerator) truncate(path string) (Type, bool) {
+	if g.opts.MaxDepth <= 0 || g.depth < g.opts.MaxDepth {
+		return nil, false
+	}
+	g.report(path, fmt.Errorf("%s: truncated at depth %d", path, g.opts.MaxDepth), true)
+	if g.opts.DepthFallback != "" {
+		return PrimitiveType(g.opts.DepthFallback), true
+	}
+	return PrimitiveType("any"), true
+}
+
+func (g *typeGenerator) uniqueName(name string) string {
+	result := name
+	for i := 2; g.names[result]; i++ {
+		result = name + "_" + strconv.Itoa(i)
+	}
+	g.names[result] = true
+	return result
+}
+
+func (g *typeGenerator) value(value interface{}, path string) Type {
+	if isNil(value) {
+		if pointee, ok := g.pointee(value, path); ok {
+			return pointee
+		}
+		if g.opts.NilType != "" {
+			return PrimitiveType(g.opts.NilType)
+		}
+		return PrimitiveType("unknown")
+	}
+	if custom, ok := g.opts.Types[reflect.TypeOf(value)]; ok {
+		return LiteralType{Value: custom}
+	}
+	switch v := value.(type) {
+	case Literal:
+		return g.literal(v, path)
+	case time.Time, []byte:
+		return PrimitiveType("string")
+	case json.Number:
+		return NumberType{Integer: !strings.ContainsAny(string(v), ".eE")}
+	case document:
+		return g.document(v, path)
+	case typed:
+		return v.t
+	case union:
+		return g.union(v, path)
+	case optional:
+		return g.value(v.value, path)
+	case *OrderedMap:
+		return g.value(g.ordered(v), path)
+	case constant:
+		if literal, ok := literalOf(reflect.ValueOf(v.value)); ok {
+			return LiteralType{Value: literal, Constant: true}
+		}
+		return g.fail(path, fmt.Errorf("%s: %T cannot be a literal type", path, v.value))
+	case enum:
+		return g.enum(v, path)
+	case tuple:
+		return g.tuple(v, path)
+	case secret:
+		if g.secrets == nil {
+			g.secrets = map[string][]string{}
+		}
+		g.secrets[path] = append(g.secrets[path], v.value)
+		return PrimitiveType("string")
+	case map[string]interface{}:
+		if fallback, ok := g.truncate(path); ok {
+			return fallback
+		}
+		if cycle, ok := g.cycle(path, v); ok {
+			return cycle
+		}
+		return g.object(v, path)
+	case dynamic:
+		return g.dynamic(v.value, path)
+	case objects:
+		if fallback, ok := g.truncate(path); ok {
+			return fallback
+		}
+		if cycle, ok := g.cycle(path, v...); ok {
+			return cycle
+		}
+		return g.object(g.mergeObjects(v), path, v...)
+	}
+	rv := reflect.ValueOf(value)
+	if g.opts.PreferLiterals {
+		if literal, ok := literalOf(rv); ok {
+			return LiteralType{Value: literal, Constant: true}
+		}
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		return PrimitiveType("string")
+	case reflect.Bool:
+		return PrimitiveType("boolean")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return NumberType{Integer: true}
+	case reflect.Float32, reflect.Float64:
+		return NumberType{}
+	case reflect.Slice, reflect.Array:
+		return g.slice(rv, path)
+	case reflect.Map:
+		return g.record(rv, path)
+	case reflect.Pointer, reflect.Struct:
+		return g.value(g.resolve(value), path)
+	}
+	return g.unsupported(value, path)
+}
+
+// pointee types a nil pointer by the primitive or struct it points to. Other
+// nil pointers cannot be typed.
+func (g *typeGenerator) pointee(value interface{}, path string) (Type, bool) {
+	if value == nil {
+		return nil, false
+	}
+	elem := reflect.TypeOf(value).Elem()
+	for elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	if custom, ok := g.opts.Types[elem]; ok {
+		return LiteralType{Value: custom}, true
+	}
+	switch elem.Kind() {
+	case reflect.String:
+		return PrimitiveType("string"), true
+	case reflect.Bool:
+		return PrimitiveType("boolean"), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return NumberType{Integer: true}, true
+	case reflect.Float32, reflect.Float64:
+		return NumberType{}, true
+	case reflect.Struct:
+		return g.zero(elem, path)
+	}
+	return nil, false
+}
+
+// zero types the zero value of t, for values like nil pointers and empty
+// slices whose shape is only known from their Go type. A type is not expanded
+// again inside its own zero value, so self-referencing structs terminate.
+func (g *typeGenerator) zero(t reflect.Type, path string) (Type, bool) {
+	if t.Kind() == reflect.Interface || g.zeros[t] {
+		return nil, false
+	}
+	if g.zeros == nil {
+		g.zeros = map[reflect.Type]bool{}
+	}
+	g.zeros[t] = true
+	defer delete(g.zeros, t)
+	result := g.value(reflect.Zero(t).Interface(), path)
+	return result, result != nil
+}
+
+func (g *typeGenerator) enum(values enum, path string) Type {
+	if len(values) == 0 {
+		return g.fail(path, fmt.Errorf("%s: empty enum", path))
+	}
+	var result UnionType
+	seen := map[string]bool{}
+	for _, value := range values {
+		literal, ok := literalOf(reflect.ValueOf(value))
+		if !ok || reflect.ValueOf(value).Kind() == reflect.Bool {
+			return g.fail(path, fmt.Errorf("%s: enum value %v is %T, not a string or number", path, value, value))
+		}
+		if seen[literal] {
+			continue
+		}
+		seen[literal] = true
+		result.Members = append(result.Members, LiteralType{Value: literal, Constant: true})
+	}
+	var t Type = result
+	if len(result.Members) == 1 {
+		t = result.Members[0]
+	}
+	if g.names == nil || !g.opts.HoistEnums {
+		return t
+	}
+	key := path[strings.LastIndex(path, ".")+1:]
+	name := SanitizeIdentifier(strings.TrimRight(key, "[]"))
+	if g.depth > 1 {
+		name = g.scope + name
+	}
+	// The same enum is seen once per object when objects are merged.
+	signature := name + " = " + RenderType(t, TypeOptions{})
+	if existing, ok := g.enums[signature]; ok {
+		g.identifiers[path] = existing
+		return ReferenceType{Name: existing}
+	}
+	if g.enums == nil {
+		g.enums = map[string]string{}
+	}
+	g.enums[signature] = g.uniqueName(name)
+	g.identifiers[path] = g.enums[signature]
+	g.declarations = append(g.declarations, declaration{name: g.enums[signature], alias: t})
+	return ReferenceType{Name: g.enums[signature]}
+}
+
+func (g *typeGenerator) tuple(values tuple, path string) Type {
+	result := TupleType{Elements: make([]TupleElement, len(values))}
+	for i, value := range values {
+		value, isOptional := unwrapOptional(value)
+		if !isOptional && i > 0 && result.Elements[i-1].Optional {
+			return g.fail(path, fmt.Errorf("%s: optional tuple element followed by a required one", path))
+		}
+		element := g.value(value, path+"["+strconv.Itoa(i)+"]")
+		if element == nil {
+			element = PrimitiveType("unknown")
+		}
+		result.Elements[i] = TupleElement{Type: element, Optional: isOptional}
+	}
+	return result
+}
+
+func (g *typeGenerator) slice(rv reflect.Value, path string) Type {
+	values := make([]interface{}, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+	if len(values) == 0 {
+		// Empty slices fall back to the static type of their elements.
+		if element, ok := g.zero(rv.Type().Elem(), path+"[]"); ok {
+			return ArrayType{Element: element}
+		}
+		return ArrayType{Element: PrimitiveType("any")}
+	}
+	if override, ok := g.override(path + "[]"); ok {
+		return ArrayType{Element: override}
+	}
+	element := g.value(g.mergeValues(values), path+"[]")
+	if element == nil {
+		element = PrimitiveType("any")
+	}
+	return ArrayType{Element: element}
+}
+
+func (g *typeGenerator) dynamic(input map[string]interface{}, path string) Type {
+	if len(input) == 0 {
+		return RecordType{Key: PrimitiveType("string"), Value: PrimitiveType("unknown")}
+	}
+	keys := sortedKeys(input, nil)
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		values[i] = input[key]
+	}
+	return RecordType{
+		Key:   PrimitiveType("string"),
+		Value: g.value(g.mergeValues(values), path+"[]"),
+		Index: true,
+	}
+}
+
+// record types Go maps such as map[string]string as a Record. Empty maps
+// fall back to the static type of their values.
+func (g *typeGenerator) record(rv reflect.Value, path string) Type {
+	var key Type
+	switch rv.Type().Key().Kind() {
+	case reflect.String:
+		key = PrimitiveType("string")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		key = PrimitiveType("number")
+	default:
+		return g.unsupported(rv.Interface(), path)
+	}
+	var element interface{}
+	if rv.Len() == 0 {
+		if rv.Type().Elem().Kind() != reflect.Interface {
+			element = reflect.Zero(rv.Type().Elem()).Interface()
+		}
+	} else {
+		entries := rv.MapKeys()
+		sort.Slice(entries, func(i, j int) bool {
+			return fmt.Sprint(entries[i].Interface()) < fmt.Sprint(entries[j].Interface())
+		})
+		values := make([]interface{}, len(entries))
+		for i, entry := range entries {
+			values[i] = rv.MapIndex(entry).Interface()
+		}
+		element = g.mergeValues(values)
+	}
+	value := g.value(element, path+"[]")
+	if value == nil {
+		value = PrimitiveType("unknown")
+	}
+	return RecordType{Key: key, Value: value}
+}
+
+// list returns value as a slice or array unless it is typed as a single
+// value, like []byte, an Enum, a Tuple or a type listed in Types.
+func (g *typeGenerator) list(value interface{}) (reflect.Value, bool) {
+	switch value.(type) {
+	case []byte, enum, tuple:
+		return reflect.Value{}, false
+	}
+	if _, ok := g.opts.Types[reflect.TypeOf(value)]; ok {
+		return reflect.Value{}, false
+	}
+	rv := reflect.ValueOf(value)
+	return rv, rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array
+}
+
+// union infers every member, dropping members that render the same.
+func (g *typeGenerator) union(members union, path string) Type {
+	var result UnionType
+	seen := map[string]int{}
+	for _, member := range members {
+		t := g.value(member, path)
+		if t == nil {
+			continue
+		}
+		rendered := RenderType(t, TypeOptions{})
+		if i, ok := seen[rendered]; ok {
+			// Integers and floats together are floats.
+			if number, ok := t.(NumberType); ok && !number.Integer {
+				result.Members[i] = number
+			}
+			continue
+		}
+		seen[rendered] = len(result.Members)
+		result.Members = append(result.Members, t)
+	}
+	switch len(result.Members) {
+	case 0:
+		return nil
+	case 1:
+		return result.Members[0]
+	}
+	return result
+}
+
+func (g *typeGenerator) literal(value Literal, path string) Type {
+	value = Literal(dedent(string(value)))
+	if !g.legacy {
+		if err := validateLiteral(value); err != nil {
+			return g.fail(path, fmt.Errorf("%s: %w", path, err))
+		}
+	}
+	return LiteralType{Value: string(value)}
+}
+
+// document infers the decoded JSON document, keeping numbers as
+// json.Number so that integers can be told apart from floats.
+func (g *typeGenerator) document(value document, path string) Type {
+	decoder := json.NewDecoder(bytes.NewReader(value.raw))
+	decoder.UseNumber()
+	var decoded interface{}
+	err := decoder.Decode(&decoded)
+	if err == nil && decoder.More() {
+		err = errors.New("unexpected data after document")
+	}
+	if err != nil {
+		g.report(path, fmt.Errorf("%s: invalid JSON: %w", path, err), g.opts.Lenient)
+		return PrimitiveType("string")
+	}
+	return g.value(decoded, path)
+}
+
+// override returns the type Overrides forces at path, noting that it was
+// applied.
+func (g *typeGenerator) override(path string) (Type, bool) {
+	override, ok := g.opts.Overrides[path]
+	if !ok {
+		return nil, false
+	}
+	if override == "" {
+		g.report(path, fmt.Errorf("%s: override is empty", path), false)
+		return PrimitiveType("unknown"), true
+	}
+	g.diagnostics = append(g.diagnostics, Diagnostic{
+		Severity: SeverityInfo,
+		Path:     path,
+		Message:  "type overridden with " + override,
+	})
+	return LiteralType{Value: override}, true
+}
+
+func (g *typeGenerator) unsupported(value interface{}, path string) Type {
+	if g.legacy {
+		return nil
+	}
+	return g.fail(path, fmt.Errorf("%s: unsupported type %T", path, value))
+}
+
+// fail records err, or only warns about it when inferring leniently or with
+// a FallbackType, and returns the type to use in place of the offending
+// value.
+func (g *typeGenerator) fail(path string, err error) Type {
+	switch g.opts.FallbackType {
+	case "unknown", "any":
+		g.report(path, err, true)
+		return PrimitiveType(g.opts.FallbackType)
+	case "error":
+		g.report(path, err, false)
+		return PrimitiveType("unknown")
+	}
+	g.report(path, err, g.opts.Lenient)
+	return PrimitiveType("unknown")
+}
+
+// report records the problem err found at path as a diagnostic. Warnings
+// are passed to Warn unless WarningsAsErrors turns them into errors.
+func (g *typeGenerator) report(path string, err error, warning bool) {
+	diagnostic := Diagnostic{
+		Severity: SeverityError,
+		Path:     path,
+		Message:  strings.TrimPrefix(err.Error(), path+": "),
+	}
+	if warning && !g.opts.WarningsAsErrors {
+		diagnostic.Severity = SeverityWarning
+		if g.opts.Warn != nil {
+			g.opts.Warn(err.Error())
+		}
+	} else {
+		g.errs = append(g.errs, err)
+	}
+	g.diagnostics = append(g.diagnostics, diagnostic)
+}
+
+// mergeObjects combines objects into a single shape. Keys missing from some
+// of them become optional and conflicting values become unions.
+func (g *typeGenerator) mergeObjects(objects []map[string]interface{}) map[string]interface{} {
+	values := map[string][]interface{}{}
+	for _, object := range objects {
+		for key, value := range object {
+			values[key] = append(values[key], value)
+		}
+	}
+	result := map[string]interface{}{}
+	for key, list := range values {
+		present := make([]interface{}, 0, len(list))
+		wrapped := false
+		for _, value := range list {
+			value, ok := unwrapOptional(value)
+			wrapped = wrapped || ok
+			if !isNil(value) {
+				present = append(present, value)
+			}
+		}
+		var merged interface{}
+		if len(present) > 0 {
+			merged = g.mergeValues(present)
+		}
+		if wrapped || len(present) < len(objects) {
+			merged = optional{value: merged}
+		}
+		result[key] = merged
+	}
+	return result
+}
+
+// mergeValues folds every object into one merged shape and every slice
+// into one combined list so their elements merge too.
+func (g *typeGenerator) mergeValues(values []interface{}) interface{} {
+	var shapes objects
+	var elements []interface{}
+	hasSlice := false
+	var members union
+	for _, value := range values {
+		value = g.resolve(value)
+		if m, ok := value.(*OrderedMap); ok {
+			value = g.ordered(m)
+		}
+		if object, ok := value.(map[string]interface{}); ok {
+			shapes = append(shapes, object)
+			continue
+		}
+		if merged, ok := value.(objects); ok {
+			shapes = append(shapes, merged...)
+			continue
+		}
+		if rv, ok := g.list(value); ok {
+			for i := 0; i < rv.Len(); i++ {
+				elements = append(elements, rv.Index(i).Interface())
+			}
+			hasSlice = true
+			continue
+		}
+		members = append(members, value)
+	}
+	if hasSlice {
+		members = append(union{elements}, members...)
+	}
+	switch len(shapes) {
+	case 0:
+	case 1:
+		members = append(union{shapes[0]}, members...)
+	default:
+		members = append(union{shapes}, members...)
+	}
+	if len(members) == 1 {
+		return members[0]
+	}
+	return members
+}
+
+// dedent drops blank leading and trailing lines from a multi-line fragment
+// and the indentation its continuation lines share, so the renderer can
+// indent it at any depth.
+func dedent(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	if !strings.Contains(value, "\n") {
+		return value
+	}
+	lines := strings.Split(value, "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	var prefix *string
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if prefix == nil {
+			prefix = &indent
+		}
+		for !strings.HasPrefix(indent, *prefix) {
+			*prefix = (*prefix)[:len(*prefix)-1]
+		}
+	}
+	lines[0] = strings.TrimSpace(lines[0])
+	for i := 1; i < len(lines); i++ {
+		if prefix != nil {
+			lines[i] = strings.TrimPrefix(lines[i], *prefix)
+		}
+		lines[i] = strings.TrimRight(lines[i], " \t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// literalOf renders strings, numbers and booleans as TypeScript literal
+// types. Floats use exponents only where JavaScript would.
+func literalOf(rv reflect.Value) (string, bool) {
+	switch rv.Kind() {
+	case reflect.String:
+		return quoteString(rv.String()), true
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return "", false
+		}
+		bits := 64
+		if rv.Kind() == reflect.Float32 {
+			bits = 32
+		}
+		if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+			// JavaScript writes exponents without padding: 1e-7, 1e+21.
+			mantissa, exponent, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, bits), "e")
+			return mantissa + "e" + exponent[:1] + strings.TrimLeft(exponent[1:], "0"), true
+		}
+		return strconv.FormatFloat(f, 'f', -1, bits), true
+	}
+	return "", false
+}
+
+// validateLiteral rejects fragments that would break the block they are
+// written into. Brackets inside string literals are not counted.
+func validateLiteral(value Literal) error {
+	if strings.TrimSpace(string(value)) == "" {
+		return fmt.Errorf("empty literal")
+	}
+	var stack []rune
+	var quote rune
+	escaped := false
+	for _, r := range value {
+		if quote != 0 {
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == quote:
+				quote = 0
+			}
+			continue
+		}
+		switch r {
+		case '"', '\'', '`':
+			quote = r
+		case '{', '(', '[':
+			stack = append(stack, r)
+		case '}', ')', ']':
+			open := map[rune]rune{'}': '{', ')': '(', ']': '['}[r]
+			if len(stack) == 0 || stack[len(stack)-1] != open {
+				return fmt.Errorf("literal %q has unbalanced %q", value, r)
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if quote != 0 {
+		return fmt.Errorf("literal %q has an unterminated string", value)
+	}
+	if len(stack) > 0 {
+		return fmt.Errorf("literal %q has unbalanced %q", value, stack[len(stack)-1])
+	}
+	return nil
+}
+
+func unwrapOptional(value interface{}) (interface{}, bool) {
+	wrapped := false
+	for {
+		o, ok := value.(optional)
+		if !ok {
+			return value, wrapped
+		}
+		value = o.value
+		wrapped = true
+	}
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	rv := reflect.ValueOf(value)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
+
+// propertyKey quotes keys that are not valid identifiers. Reserved words
+// are allowed as property names so they are left bare.
+func propertyKey(key string) string {
+	if isIdentifier(key) {
+		return key
+	}
+	return quoteString(key)
+}
+
+func isIdentifier(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		if r == '_' || r == '$' || unicode.IsLetter(r) {
+			continue
+		}
+		if i > 0 && unicode.IsDigit(r) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func quoteString(value string) string {
+	plain := !strings.ContainsAny(value, "\u2028\u2029")
+	for i := 0; plain && i < len(value); i++ {
+		plain = value[i] >= 0x20 && value[i] != '"' && value[i] != '\\'
+	}
+	if plain {
+		return `"` + value + `"`
+	}
+	var builder strings.Builder
+	builder.WriteString("\"")
+	for _, r := range value {
+		switch {
+		case r == '"' || r == '\\':
+			builder.WriteRune('\\')
+			builder.WriteRune(r)
+		case r == '\n':
+			builder.WriteString("\\n")
+		case r < 0x20 || r == 0x2028 || r == 0x2029:
+			fmt.Fprintf(&builder, "\\u%04x", r)
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	builder.WriteString("\"")
+	return builder.String()
+}
+
+// SanitizeIdentifier turns a key into the PascalCase identifier
+// InferInterfaces names its interface after, dropping every character that
+// is not a letter or digit, so that my-api becomes MyApi.
+func SanitizeIdentifier(key string) string {
+	var builder strings.Builder
+	upper := true
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if builder.Len() == 0 && unicode.IsDigit(r) {
+			builder.WriteRune('_')
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		builder.WriteRune(r)
+	}
+	if builder.Len() == 0 {
+		return "_"
+	}
 	return builder.String()
 }
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// keyLess orders keys by opts.KeyPriority, then by opts.Less.
+func keyLess(opts TypeOptions) func(a, b string) bool {
+	if len(opts.KeyPriority) == 0 {
+		return opts.Less
+	}
+	priority := make(map[string]int, len(opts.KeyPriority))
+	for i, key := range opts.KeyPriority {
+		if _, ok := priority[key]; !ok {
+			priority[key] = i
+		}
+	}
+	return func(a, b string) bool {
+		i, pinnedA := priority[a]
+		j, pinnedB := priority[b]
+		switch {
+		case pinnedA && pinnedB:
+			return i < j
+		case pinnedA || pinnedB:
+			return pinnedA
+		case opts.Less != nil:
+			return opts.Less(a, b)
+		}
+		return a < b
+	}
+}
+
+func sortedKeys(input map[string]interface{}, less func(a, b string) bool) []string {
+	keys := make([]string, 0, len(input))
+	for key := range input {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if less == nil {
+		return keys
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return less(keys[i], keys[j])
+	})
+	return keys
+}
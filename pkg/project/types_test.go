@@ -0,0 +1,1597 @@
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var deterministicInput = map[string]interface{}{
+	"zeta":  "value",
+	"alpha": 1,
+	"type":  "sst.aws.Bucket",
+	"mid": map[string]interface{}{
+		"y":    1.5,
+		"b":    "value",
+		"type": "nested",
+		"a": map[string]interface{}{
+			"z": "value",
+			"c": 2,
+		},
+	},
+}
+
+func TestInferTypesDeterministic(t *testing.T) {
+	expected := inferTypes(deterministicInput, "")
+	for i := 0; i < 20; i++ {
+		result := inferTypes(deterministicInput, "")
+		if result != expected {
+			t.Fatalf("run %d produced different output:\n%s\nexpected:\n%s", i, result, expected)
+		}
+	}
+}
+
+func TestInferTypesSorted(t *testing.T) {
+	expected := "{\n" +
+		"  alpha: number\n" +
+		"  mid: {\n" +
+		"    a: {\n" +
+		"      c: number\n" +
+		"      z: string\n" +
+		"    }\n" +
+		"    b: string\n" +
+		"    type: \"nested\"\n" +
+		"    y: number\n" +
+		"  }\n" +
+		"  type: \"sst.aws.Bucket\"\n" +
+		"  zeta: string\n" +
+		"}"
+	result := inferTypes(deterministicInput, "")
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestInferTypesTypeFirst(t *testing.T) {
+	expected := "{\n" +
+		"  type: \"sst.aws.Bucket\"\n" +
+		"  alpha: number\n" +
+		"  mid: {\n" +
+		"    type: \"nested\"\n" +
+		"    a: {\n" +
+		"      c: number\n" +
+		"      z: string\n" +
+		"    }\n" +
+		"    b: string\n" +
+		"    y: number\n" +
+		"  }\n" +
+		"  zeta: string\n" +
+		"}"
+	for i := 0; i < 20; i++ {
+		result := inferTypesWithOptions(deterministicInput, TypeOptions{Less: TypeFirst}, "")
+		if result != expected {
+			t.Fatalf("Expected %v, got %v", expected, result)
+		}
+	}
+}
+
+func TestInferTypesSlices(t *testing.T) {
+	examples := map[string]interface{}{
+		"any[]":               []interface{}{},
+		"string[]":            []string{"a", "b"},
+		"number[]":            []interface{}{1, 2.5},
+		"string[][]":          [][]string{{"a"}, {"b"}},
+		"(string | number)[]": []interface{}{"a", 1},
+		"{\n    name: string\n  }[]": []map[string]interface{}{
+			{"name": "a"},
+			{"name": "b"},
+		},
+	}
+	for expected, input := range examples {
+		result := inferTypes(map[string]interface{}{"list": input}, "")
+		if result != "{\n  list: "+expected+"\n}" {
+			t.Errorf("Expected %v, got %v", expected, result)
+		}
+	}
+}
+
+func TestInferTypesKinds(t *testing.T) {
+	examples := []struct {
+		input    interface{}
+		expected string
+	}{
+		{int(1), "number"},
+		{int8(1), "number"},
+		{int16(1), "number"},
+		{int32(1), "number"},
+		{int64(1), "number"},
+		{uint(1), "number"},
+		{uint8(1), "number"},
+		{uint16(1), "number"},
+		{uint32(1), "number"},
+		{uint64(1), "number"},
+		{uintptr(1), "number"},
+		{float32(1), "number"},
+		{float64(1), "number"},
+		{true, "boolean"},
+		{"value", "string"},
+	}
+	for _, example := range examples {
+		result := inferTypes(map[string]interface{}{"key": example.input}, "")
+		if result != "{\n  key: "+example.expected+"\n}" {
+			t.Errorf("%T: Expected %v, got %v", example.input, example.expected, result)
+		}
+	}
+}
+
+func TestInferTypesUnsupported(t *testing.T) {
+	input := map[string]interface{}{
+		"name": "value",
+		"foo": map[string]interface{}{
+			"bar": map[string]interface{}{
+				"baz": make(chan int),
+			},
+		},
+		"handler": func() {},
+	}
+	_, err := InferTypes(input, TypeOptions{})
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	expected := "foo.bar.baz: unsupported type chan int\nhandler: unsupported type func()"
+	if err.Error() != expected {
+		t.Errorf("Expected %v, got %v", expected, err.Error())
+	}
+}
+
+func TestInferTypesLenient(t *testing.T) {
+	input := map[string]interface{}{
+		"foo": map[string]interface{}{
+			"bar": []interface{}{make(chan int)},
+		},
+	}
+	var warnings []string
+	result, err := InferTypes(input, TypeOptions{
+		Lenient: true,
+		Warn: func(warning string) {
+			warnings = append(warnings, warning)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{\n  foo: {\n    bar: unknown[]\n  }\n}"
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	if !reflect.DeepEqual(warnings, []string{"foo.bar[]: unsupported type chan int"}) {
+		t.Errorf("Unexpected warnings %v", warnings)
+	}
+}
+
+func TestInferTypesNil(t *testing.T) {
+	var pointer *string
+	input := map[string]interface{}{
+		"type":    nil,
+		"name":    nil,
+		"pointer": pointer,
+		"outer": map[string]interface{}{
+			"inner": map[string]interface{}{
+				"value": nil,
+			},
+		},
+	}
+	expected := "{\n" +
+		"  name?: unknown\n" +
+		"  outer: {\n" +
+		"    inner: {\n" +
+		"      value?: unknown\n" +
+		"    }\n" +
+		"  }\n" +
+		"  pointer?: string\n" +
+		"  type?: unknown\n" +
+		"}"
+	result, err := InferTypes(input, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	if result := inferTypes(input, "  "); !strings.Contains(result, "type?: unknown") {
+		t.Errorf("Expected optional type key, got %v", result)
+	}
+	result, _ = InferTypes(map[string]interface{}{"name": nil}, TypeOptions{NilType: "undefined"})
+	if result != "{\n  name?: undefined\n}" {
+		t.Errorf("Expected undefined fallback, got %v", result)
+	}
+}
+
+func TestInferTypesKeys(t *testing.T) {
+	examples := map[string]string{
+		"name":          "name",
+		"_private":      "_private",
+		"$ref":          "$ref",
+		"bucket2":       "bucket2",
+		"default":       "default",
+		"class":         "class",
+		"my-api":        `"my-api"`,
+		"auth.callback": `"auth.callback"`,
+		"0":             `"0"`,
+		"2fa":           `"2fa"`,
+		"with space":    `"with space"`,
+		`say "hi"`:      `"say \"hi\""`,
+		`back\slash`:    `"back\\slash"`,
+		"":              `""`,
+	}
+	for key, expected := range examples {
+		result := inferTypes(map[string]interface{}{key: "value"}, "")
+		if result != "{\n  "+expected+": string\n}" {
+			t.Errorf("Expected %v, got %v", expected, result)
+		}
+	}
+}
+
+var formattingInput = map[string]interface{}{
+	"MyBucket": map[string]interface{}{
+		"type": "sst.aws.Bucket",
+		"name": "bucket",
+	},
+	"MyQueue": map[string]interface{}{
+		"type": "sst.aws.Queue",
+		"url":  "url",
+		"dlq": map[string]interface{}{
+			"url": "url",
+		},
+	},
+	"stage": "production",
+}
+
+func TestInferTypesFormatting(t *testing.T) {
+	examples := map[string]TypeOptions{
+		"types_default.golden":    {},
+		"types_tabs_semi.golden":  {Indent: "\t", FieldTerminator: ";", TerminateBlocks: true, TrailingNewline: true},
+		"types_comma_open.golden": {Indent: "    ", FieldTerminator: ","},
+	}
+	for file, opts := range examples {
+		result, err := InferTypes(formattingInput, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectGolden(t, file, result)
+	}
+	result, _ := InferTypes(formattingInput, TypeOptions{})
+	if result != inferTypesWithOptions(formattingInput, TypeOptions{}, "") {
+		t.Errorf("Default options changed the output: %v", result)
+	}
+}
+
+func expectGolden(t *testing.T, file string, result string) {
+	t.Helper()
+	expected, err := os.ReadFile(filepath.Join("testdata", file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != string(expected) {
+		t.Errorf("%s: Expected %v, got %v", file, string(expected), result)
+	}
+}
+
+func TestInferInterfaces(t *testing.T) {
+	examples := map[string]TypeOptions{
+		"interfaces.golden":        {},
+		"interfaces_nested.golden": {HoistNested: true, TrailingNewline: true},
+	}
+	for file, opts := range examples {
+		result, err := InferInterfaces(formattingInput, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectGolden(t, file, result)
+	}
+}
+
+func TestInferInterfacesCollisions(t *testing.T) {
+	input := map[string]interface{}{
+		"my_api":   map[string]interface{}{"url": "url"},
+		"my-api":   map[string]interface{}{"url": "url"},
+		"MyApi":    map[string]interface{}{"url": "url"},
+		"Resource": map[string]interface{}{"url": "url"},
+	}
+	expected := "export interface Resource {\n" +
+		"  MyApi: MyApi\n" +
+		"  Resource: Resource_2\n" +
+		"  \"my-api\": MyApi_2\n" +
+		"  my_api: MyApi_3\n" +
+		"}"
+	for i := 0; i < 10; i++ {
+		result, err := InferInterfaces(input, TypeOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(result, expected) {
+			t.Fatalf("Expected %v, got %v", expected, result)
+		}
+	}
+}
+
+func TestInferTypesMergeSlices(t *testing.T) {
+	input := map[string]interface{}{
+		"empty": []interface{}{},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"type":     "allow",
+				"id":       "a",
+				"priority": 1,
+				"match": map[string]interface{}{
+					"path": "/a",
+				},
+			},
+			map[string]interface{}{
+				"type": "deny",
+				"id":   2,
+				"match": map[string]interface{}{
+					"path":   "/b",
+					"method": "GET",
+				},
+			},
+			map[string]interface{}{
+				"type": "allow",
+				"id":   "c",
+			},
+		},
+	}
+	expected := "{\n" +
+		"  empty: any[]\n" +
+		"  rules: {\n" +
+		"    id: string | number\n" +
+		"    match?: {\n" +
+		"      method?: string\n" +
+		"      path: string\n" +
+		"    }\n" +
+		"    priority?: number\n" +
+		"    type: \"allow\" | \"deny\"\n" +
+		"  }[]\n" +
+		"}"
+	result, err := InferTypes(input, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	result, _ = InferTypes(map[string]interface{}{"list": []interface{}{"a", 1}}, TypeOptions{})
+	if result != "{\n  list: (string | number)[]\n}" {
+		t.Errorf("Expected a parenthesized union, got %v", result)
+	}
+}
+
+func TestInferTypesLiteral(t *testing.T) {
+	input := map[string]interface{}{
+		"binding": Literal(`import("@cloudflare/workers-types").R2Bucket`),
+		"level":   Union([]string{"debug", "info"}),
+		"shape":   Literal("{ id: string }"),
+	}
+	expected := "{\n" +
+		"  binding: import(\"@cloudflare/workers-types\").R2Bucket\n" +
+		"  level: \"debug\" | \"info\"\n" +
+		"  shape: { id: string }\n" +
+		"}"
+	result, err := InferTypes(input, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestInferTypesInvalidLiteral(t *testing.T) {
+	examples := map[string]Literal{
+		"empty":    "",
+		"blank":    "  ",
+		"open":     "{ id: string",
+		"close":    "string }",
+		"mismatch": "(string]",
+		"quote":    `"open`,
+	}
+	for key, value := range examples {
+		_, err := InferTypes(map[string]interface{}{
+			"nested": map[string]interface{}{key: value},
+		}, TypeOptions{})
+		if err == nil || !strings.HasPrefix(err.Error(), "nested."+key+": ") {
+			t.Errorf("%s: Expected an error, got %v", key, err)
+		}
+	}
+	if _, err := InferTypes(map[string]interface{}{"brace": Literal(`"}" | "{"`)}, TypeOptions{}); err != nil {
+		t.Errorf("Expected braces inside strings to be accepted, got %v", err)
+	}
+}
+
+func TestInferTypesDiscriminators(t *testing.T) {
+	input := map[string]interface{}{
+		"MyBucket": map[string]interface{}{
+			"type": "sst.aws.Bucket",
+			"cors": map[string]interface{}{
+				"type": "strict",
+				"kind": "cors",
+			},
+		},
+		"kind": `say "hi"`,
+	}
+	examples := []struct {
+		opts     TypeOptions
+		expected string
+	}{
+		{
+			TypeOptions{},
+			"{\n  MyBucket: {\n    cors: {\n      kind: string\n      type: \"strict\"\n    }\n    type: \"sst.aws.Bucket\"\n  }\n  kind: string\n}",
+		},
+		{
+			TypeOptions{Discriminators: []string{"type", "kind"}},
+			"{\n  MyBucket: {\n    cors: {\n      kind: \"cors\"\n      type: \"strict\"\n    }\n    type: \"sst.aws.Bucket\"\n  }\n  kind: \"say \\\"hi\\\"\"\n}",
+		},
+		{
+			TypeOptions{Discriminators: []string{"type", "kind"}, ShallowDiscriminators: true},
+			"{\n  MyBucket: {\n    cors: {\n      kind: string\n      type: string\n    }\n    type: \"sst.aws.Bucket\"\n  }\n  kind: \"say \\\"hi\\\"\"\n}",
+		},
+		{
+			TypeOptions{Discriminators: []string{}},
+			"{\n  MyBucket: {\n    cors: {\n      kind: string\n      type: string\n    }\n    type: string\n  }\n  kind: string\n}",
+		},
+	}
+	for _, example := range examples {
+		result, err := InferTypes(input, example.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != example.expected {
+			t.Errorf("Expected %v, got %v", example.expected, result)
+		}
+	}
+
+	// Without an indent inferTypes only skips the discriminators of the
+	// root object.
+	legacy := map[string]interface{}{
+		"type":     "app",
+		"MyBucket": map[string]interface{}{"type": "sst.aws.Bucket"},
+	}
+	expected := "{\n  MyBucket: {\n    type: \"sst.aws.Bucket\"\n  }\n  type: string\n}"
+	if result := inferTypes(legacy); result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestInferTypesDiscriminatorNotString(t *testing.T) {
+	var warnings []string
+	result, err := InferTypes(map[string]interface{}{
+		"MyBucket": map[string]interface{}{"type": 1},
+	}, TypeOptions{Warn: func(warning string) {
+		warnings = append(warnings, warning)
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "{\n  MyBucket: {\n    type: string\n  }\n}" {
+		t.Errorf("Expected string fallback, got %v", result)
+	}
+	if !reflect.DeepEqual(warnings, []string{"MyBucket.type: discriminator is int, not a string"}) {
+		t.Errorf("Unexpected warnings %v", warnings)
+	}
+}
+
+func TestInferTypesOptional(t *testing.T) {
+	input := map[string]interface{}{
+		"name":    Optional("bucket"),
+		"missing": Optional(nil),
+		"binding": Optional(Literal("R2Bucket")),
+		"tags":    Optional([]string{"a"}),
+		"config": Optional(map[string]interface{}{
+			"port":  Optional(8080),
+			"hosts": []interface{}{Optional("a")},
+		}),
+	}
+	expected := "{\n" +
+		"  binding?: R2Bucket\n" +
+		"  config?: {\n" +
+		"    hosts: string[]\n" +
+		"    port?: number\n" +
+		"  }\n" +
+		"  missing?: unknown\n" +
+		"  name?: string\n" +
+		"  tags?: string[]\n" +
+		"}"
+	result, err := InferTypes(input, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func syntheticResources(count int) map[string]interface{} {
+	input := map[string]interface{}{}
+	for i := 0; i < count; i++ {
+		input[fmt.Sprintf("Resource%d", i)] = map[string]interface{}{
+			"type": "sst.aws.Function",
+			"name": "name",
+			"arn":  "arn",
+			"url":  "url",
+			"config": map[string]interface{}{
+				"memory":  1024,
+				"timeout": 30,
+				"layers":  []string{"a", "b"},
+			},
+		}
+	}
+	return input
+}
+
+type failingWriter struct {
+	writes int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return 0, errors.New("disk full")
+}
+
+func TestRender(t *testing.T) {
+	input := syntheticResources(50)
+	var builder strings.Builder
+	if err := Render(&builder, input); err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := InferTypes(input, TypeOptions{})
+	if builder.String() != expected {
+		t.Error("Render and InferTypes disagree")
+	}
+}
+
+func TestRenderWriteError(t *testing.T) {
+	w := &failingWriter{}
+	err := Render(w, syntheticResources(1000))
+	if err == nil || err.Error() != "disk full" {
+		t.Fatalf("Expected the write error, got %v", err)
+	}
+	if w.writes != 1 {
+		t.Errorf("Expected rendering to stop after the first failed write, got %d writes", w.writes)
+	}
+}
+
+func BenchmarkInferTypes(b *testing.B) {
+	input := syntheticResources(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result, _ := InferTypes(input, TypeOptions{})
+		io.WriteString(io.Discard, result)
+	}
+}
+
+func BenchmarkRender(b *testing.B) {
+	input := syntheticResources(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Render(io.Discard, input)
+	}
+}
+
+func TestInferTypesCycles(t *testing.T) {
+	self := map[string]interface{}{"name": "self"}
+	self["self"] = self
+
+	a := map[string]interface{}{"name": "a"}
+	b := map[string]interface{}{"name": "b"}
+	a["b"] = b
+	b["a"] = a
+
+	list := map[string]interface{}{"name": "list"}
+	list["children"] = []interface{}{list, map[string]interface{}{"children": []interface{}{list}}}
+
+	examples := map[string]struct {
+		input    map[string]interface{}
+		expected string
+	}{
+		"self":  {map[string]interface{}{"Node": self}, "Node.self: cyclic reference to Node"},
+		"pair":  {map[string]interface{}{"A": a}, "A.b.a: cyclic reference to A"},
+		"root":  {self, "self: cyclic reference to the root object"},
+		"slice": {map[string]interface{}{"Tree": list}, "Tree.children[]: cyclic reference to Tree"},
+	}
+	for name, example := range examples {
+		_, err := InferTypes(example.input, TypeOptions{})
+		if err == nil || err.Error() != example.expected {
+			t.Errorf("%s: Expected %v, got %v", name, example.expected, err)
+		}
+	}
+}
+
+func TestInferTypesSharedObject(t *testing.T) {
+	shared := map[string]interface{}{"url": "url"}
+	input := map[string]interface{}{
+		"A": map[string]interface{}{"api": shared},
+		"B": map[string]interface{}{"api": shared, "nested": map[string]interface{}{"api": shared}},
+	}
+	if _, err := InferTypes(input, TypeOptions{}); err != nil {
+		t.Errorf("Expected shared objects to be accepted, got %v", err)
+	}
+}
+
+func TestInferInterfacesCycles(t *testing.T) {
+	node := map[string]interface{}{"name": "node"}
+	node["parent"] = node
+	result, err := InferInterfaces(map[string]interface{}{"Node": node}, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "export interface Resource {\n" +
+		"  Node: Node\n" +
+		"}\n\n" +
+		"export interface Node {\n" +
+		"  name: string\n" +
+		"  parent: Node\n" +
+		"}"
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+type cidr struct {
+	ip   string
+	mask int
+}
+
+func TestInferTypesWellKnownTypes(t *testing.T) {
+	input := map[string]interface{}{
+		"created": time.Now(),
+		"count":   json.Number("3"),
+		"blob":    []byte("data"),
+		"blobs":   []interface{}{[]byte("a"), []byte("b")},
+		"network": cidr{ip: "10.0.0.0", mask: 16},
+	}
+	expected := "{\n" +
+		"  blob: string\n" +
+		"  blobs: string[]\n" +
+		"  count: number\n" +
+		"  created: Date\n" +
+		"  network: `${string}/${number}`\n" +
+		"}"
+	result, err := InferTypes(input, TypeOptions{
+		Types: map[reflect.Type]string{
+			reflect.TypeOf(time.Time{}): "Date",
+			reflect.TypeOf(cidr{}):      "`${string}/${number}`",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	result, _ = InferTypes(map[string]interface{}{"created": time.Now()}, TypeOptions{})
+	if result != "{\n  created: string\n}" {
+		t.Errorf("Expected time.Time to default to string, got %v", result)
+	}
+}
+
+func TestInferTypesRecords(t *testing.T) {
+	input := map[string]interface{}{
+		"environment": map[string]string{"A": "a"},
+		"ports":       map[string]int{},
+		"byId":        map[int]string{1: "a"},
+		"tags":        Dynamic(map[string]interface{}{"team": "core", "cost": 1}),
+		"empty":       Dynamic(map[string]interface{}{}),
+		"rules": Dynamic(map[string]interface{}{
+			"a": map[string]interface{}{"path": "/a"},
+			"b": map[string]interface{}{"path": "/b", "method": "GET"},
+		}),
+	}
+	expected := "{\n" +
+		"  byId: Record<number, string>\n" +
+		"  empty: Record<string, unknown>\n" +
+		"  environment: Record<string, string>\n" +
+		"  ports: Record<string, number>\n" +
+		"  rules: { [key: string]: {\n" +
+		"    method?: string\n" +
+		"    path: string\n" +
+		"  } }\n" +
+		"  tags: { [key: string]: number | string }\n" +
+		"}"
+	result, err := InferTypes(input, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+var unionInputs = []map[string]interface{}{
+	{"type": "Function", "handler": "index.handler"},
+	{"type": "Bucket", "name": "a"},
+	{"type": "Bucket", "name": "b", "public": true},
+	{"name": "untyped"},
+}
+
+func TestInferUnion(t *testing.T) {
+	result, err := InferUnion(unionInputs, "type")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{\n" +
+		"  name: string\n" +
+		"  public?: boolean\n" +
+		"  type: \"Bucket\"\n" +
+		"} | {\n" +
+		"  handler: string\n" +
+		"  type: \"Function\"\n" +
+		"} | {\n" +
+		"  name: string\n" +
+		"}"
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	result, err = InferUnion([]map[string]interface{}{
+		{"kind": "a", "type": "x"},
+		{"kind": "b", "type": "y"},
+	}, "kind", TypeOptions{Discriminators: []string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "{\n  kind: \"a\"\n  type: string\n} | {\n  kind: \"b\"\n  type: string\n}" {
+		t.Errorf("Expected only kind to be a literal, got %v", result)
+	}
+}
+
+func TestInferUnionRejectUndiscriminated(t *testing.T) {
+	_, err := InferUnion(unionInputs, "type", TypeOptions{RejectUndiscriminated: true})
+	if err == nil || err.Error() != `[3]: missing string discriminator "type"` {
+		t.Errorf("Expected missing discriminator error, got %v", err)
+	}
+}
+
+func TestDescriptions(t *testing.T) {
+	var warnings []string
+	result, err := InferTypes(map[string]interface{}{
+		"MyBucket": map[string]interface{}{
+			"name": "bucket",
+			"arn":  "arn",
+			"url":  "url",
+		},
+	}, TypeOptions{
+		Descriptions: map[string]string{
+			"MyBucket":      "A bucket",
+			"MyBucket.name": "The bucket's physical name",
+			"MyBucket.arn":  "The bucket ARN.\nUse it in IAM policies.",
+			"MyBucket.url":  "Deprecated: use the name instead",
+			"MyQueue.url":   "Missing",
+		},
+		Warn: func(warning string) { warnings = append(warnings, warning) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{\n" +
+		"  /** A bucket */\n" +
+		"  MyBucket: {\n" +
+		"    /**\n" +
+		"     * The bucket ARN.\n" +
+		"     * Use it in IAM policies.\n" +
+		"     */\n" +
+		"    arn: string\n" +
+		"    /** The bucket's physical name */\n" +
+		"    name: string\n" +
+		"    /** @deprecated use the name instead */\n" +
+		"    url: string\n" +
+		"  }\n" +
+		"}"
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	if !reflect.DeepEqual(warnings, []string{"MyQueue.url: description matches no property"}) {
+		t.Errorf("Expected missing path warning, got %v", warnings)
+	}
+}
+
+func TestReadonly(t *testing.T) {
+	result, err := InferTypes(map[string]interface{}{
+		"my-api": map[string]interface{}{
+			"url":    Optional("url"),
+			"routes": []interface{}{map[string]interface{}{"path": "/"}},
+			"config": Literal("{ port: number }"),
+		},
+	}, TypeOptions{Readonly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{\n" +
+		"  readonly \"my-api\": {\n" +
+		"    readonly config: { port: number }\n" +
+		"    readonly routes: {\n" +
+		"      readonly path: string\n" +
+		"    }[]\n" +
+		"    readonly url?: string\n" +
+		"  }\n" +
+		"}"
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestInferSecrets(t *testing.T) {
+	input := map[string]interface{}{
+		"StripeKey": map[string]interface{}{
+			"type":  "sst.sst.Secret",
+			"value": Secret("sk_live_123"),
+		},
+		"MyDatabase": map[string]interface{}{
+			"connection": map[string]interface{}{"url": Secret("postgres://db")},
+			"keys":       []interface{}{Secret("a"), Secret("b")},
+		},
+	}
+	result, secrets, err := InferSecrets(input, TypeOptions{StrictSecrets: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(result, "sk_live_123") || !strings.Contains(result, "value: string") {
+		t.Errorf("Expected secret to be typed as string, got %v", result)
+	}
+	expected := []string{"MyDatabase.connection.url", "MyDatabase.keys[]", "StripeKey.value"}
+	if !reflect.DeepEqual(secrets, expected) {
+		t.Errorf("Expected %v, got %v", expected, secrets)
+	}
+
+	// Short secrets only leak when a literal holds the whole value.
+	short := map[string]interface{}{
+		"MyBucket": map[string]interface{}{"type": "sst.aws.Bucket", "key": Secret("a")},
+	}
+	if _, _, err := InferSecrets(short, TypeOptions{StrictSecrets: true}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	input["StripeKey"].(map[string]interface{})["hint"] = Literal(`"sk_live_123"`)
+	_, _, err = InferSecrets(input, TypeOptions{StrictSecrets: true})
+	if err == nil || err.Error() != "StripeKey.value: secret value is rendered in the output" {
+		t.Errorf("Expected leaked secret error, got %v", err)
+	}
+}
+
+func TestEnum(t *testing.T) {
+	input := map[string]interface{}{
+		"MyApi": map[string]interface{}{
+			"stage": Enum("dev", "staging", "production", "dev"),
+			"routes": []interface{}{
+				map[string]interface{}{"path": "/", "version": Enum(1, 2, 3)},
+				map[string]interface{}{"path": "/users", "version": Enum(1, 2, 3)},
+			},
+		},
+	}
+	result, err := InferTypes(input, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{\n" +
+		"  MyApi: {\n" +
+		"    routes: {\n" +
+		"      path: string\n" +
+		"      version: 1 | 2 | 3\n" +
+		"    }[]\n" +
+		"    stage: \"dev\" | \"staging\" | \"production\"\n" +
+		"  }\n" +
+		"}"
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	result, err = InferInterfaces(input, TypeOptions{HoistEnums: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "export interface Resource {\n" +
+		"  MyApi: MyApi\n" +
+		"}\n\n" +
+		"export interface MyApi {\n" +
+		"  routes: {\n" +
+		"    path: string\n" +
+		"    version: MyApiVersion\n" +
+		"  }[]\n" +
+		"  stage: MyApiStage\n" +
+		"}\n\n" +
+		"export type MyApiVersion = 1 | 2 | 3\n\n" +
+		"export type MyApiStage = \"dev\" | \"staging\" | \"production\""
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	_, err = InferTypes(map[string]interface{}{"stage": Enum()}, TypeOptions{})
+	if err == nil || err.Error() != "stage: empty enum" {
+		t.Errorf("Expected empty enum error, got %v", err)
+	}
+}
+
+func TestTuple(t *testing.T) {
+	result, err := InferTypes(map[string]interface{}{
+		"MyVpc": map[string]interface{}{
+			"cidr":     Tuple("10.0.0.0/16", 443),
+			"location": Tuple(1.5, 2.5, Optional(100)),
+			"empty":    Tuple(),
+			"pairs":    []interface{}{Tuple("key", "value")},
+			"subnets": Tuple(
+				map[string]interface{}{"id": "subnet", "public": true},
+				Optional([]interface{}{"a", 1}),
+			),
+		},
+	}, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectGolden(t, "tuple.golden", result)
+}
+
+func TestOutputs(t *testing.T) {
+	input := map[string]interface{}{
+		"MyBucket": map[string]interface{}{
+			"type":   "sst.aws.Bucket",
+			"name":   "bucket",
+			"tags":   []interface{}{"a"},
+			"config": Literal("Output<string>"),
+			"dlq":    map[string]interface{}{"url": "url"},
+		},
+	}
+	result, err := InferInterfaces(input, TypeOptions{Outputs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "import { Output } from \"@pulumi/pulumi\"\n\n" +
+		"export interface Resource {\n" +
+		"  MyBucket: MyBucket\n" +
+		"}\n\n" +
+		"export interface MyBucket {\n" +
+		"  config: Output<string>\n" +
+		"  dlq: {\n" +
+		"    url: Output<string>\n" +
+		"  }\n" +
+		"  name: Output<string>\n" +
+		"  tags: Output<string[]>\n" +
+		"  type: Output<\"sst.aws.Bucket\">\n" +
+		"}"
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	result, err = InferInterfaces(input, TypeOptions{Outputs: true, OutputObjects: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "import { Output } from \"@pulumi/pulumi\"\n\n" +
+		"export interface Resource {\n" +
+		"  MyBucket: Output<MyBucket>\n" +
+		"}\n\n" +
+		"export interface MyBucket {\n" +
+		"  config: Output<string>\n" +
+		"  dlq: {\n" +
+		"    url: string\n" +
+		"  }\n" +
+		"  name: string\n" +
+		"  tags: string[]\n" +
+		"  type: \"sst.aws.Bucket\"\n" +
+		"}"
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	result, _ = InferTypes(input, TypeOptions{Outputs: true, OutputObjects: true})
+	if !strings.HasPrefix(result, "{\n  MyBucket: Output<{\n    config: Output<string>\n") {
+		t.Errorf("Expected inline object to be wrapped, got %v", result)
+	}
+
+	input = map[string]interface{}{
+		"routes": []interface{}{map[string]interface{}{"path": "/", "port": Const(1)}},
+		"ports":  []interface{}{Const(80), Const(443)},
+		"hints":  []interface{}{Literal("Output<string>")},
+	}
+	result, _ = InferTypes(input, TypeOptions{Outputs: true})
+	expected = "{\n" +
+		"  hints: Output<string>[]\n" +
+		"  ports: Output<(80 | 443)[]>\n" +
+		"  routes: {\n" +
+		"    path: Output<string>\n" +
+		"    port: Output<1>\n" +
+		"  }[]\n" +
+		"}"
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	result, _ = InferTypes(input, TypeOptions{Outputs: true, OutputObjects: true})
+	if !strings.Contains(result, "  routes: Output<{\n    path: string\n    port: 1\n  }[]>\n") {
+		t.Errorf("Expected array of objects to be wrapped whole, got %v", result)
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	deep := map[string]interface{}{"value": "leaf"}
+	for i := 0; i < 12; i++ {
+		deep = map[string]interface{}{"nested": deep}
+	}
+	input := map[string]interface{}{
+		"MyConfig": deep,
+		"MyApi": map[string]interface{}{
+			"routes": []interface{}{map[string]interface{}{"path": "/", "auth": map[string]interface{}{"type": "iam"}}},
+		},
+	}
+	var warnings []string
+	result, err := InferTypes(input, TypeOptions{
+		MaxDepth:      3,
+		DepthFallback: "Record<string, unknown>",
+		Warn:          func(warning string) { warnings = append(warnings, warning) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{\n" +
+		"  MyApi: {\n" +
+		"    routes: {\n" +
+		"      auth: Record<string, unknown>\n" +
+		"      path: string\n" +
+		"    }[]\n" +
+		"  }\n" +
+		"  MyConfig: {\n" +
+		"    nested: {\n" +
+		"      nested: Record<string, unknown>\n" +
+		"    }\n" +
+		"  }\n" +
+		"}"
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	expectedWarnings := []string{
+		"MyApi.routes[].auth: truncated at depth 3",
+		"MyConfig.nested.nested: truncated at depth 3",
+	}
+	if !reflect.DeepEqual(warnings, expectedWarnings) {
+		t.Errorf("Expected %v, got %v", expectedWarnings, warnings)
+	}
+
+	result, _ = InferTypes(input, TypeOptions{})
+	if strings.Count(result, "nested") != 12 {
+		t.Errorf("Expected no truncation by default, got %v", result)
+	}
+}
+
+func TestKeyCollisions(t *testing.T) {
+	input := map[string]interface{}{
+		"ApiCache": map[string]interface{}{"url": "url"},
+		"apiCache": map[string]interface{}{"url": "url"},
+		"MyApi": map[string]interface{}{
+			"my-route": "a",
+			"my_route": "b",
+			"Name":     "c",
+			"name":     "d",
+		},
+	}
+	var warnings []string
+	_, err := InferTypes(input, TypeOptions{
+		CheckKeyCollisions: true,
+		Warn:               func(warning string) { warnings = append(warnings, warning) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		`the root object: keys "ApiCache" and "apiCache" collide`,
+		`MyApi: keys "Name" and "name" collide`,
+	}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("Expected %v, got %v", expected, warnings)
+	}
+
+	_, err = InferTypes(input, TypeOptions{CheckIdentifierCollisions: true, RejectKeyCollisions: true})
+	var collision *KeyCollision
+	if !errors.As(err, &collision) || collision.Path != "" || collision.Key != "ApiCache" {
+		t.Fatalf("Expected key collision error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `MyApi: keys "my-route" and "my_route" collide`) {
+		t.Errorf("Expected sanitized collision, got %v", err)
+	}
+
+	warnings = nil
+	_, err = InferTypes(map[string]interface{}{
+		"MyBucket": map[string]interface{}{"name": "bucket", "url": "url"},
+	}, TypeOptions{
+		CheckIdentifierCollisions: true,
+		Warn:                      func(warning string) { warnings = append(warnings, warning) },
+	})
+	if err != nil || len(warnings) != 0 {
+		t.Errorf("Expected no collisions, got %v %v", warnings, err)
+	}
+}
+
+func TestConst(t *testing.T) {
+	result, err := InferTypes(map[string]interface{}{
+		"MyApp": map[string]interface{}{
+			"stage":   Const("production"),
+			"quote":   Const(`say "hi" \ bye`),
+			"port":    Const(8080),
+			"ratio":   Const(0.1),
+			"large":   Const(1e20),
+			"huge":    Const(1.5e300),
+			"tiny":    Const(-1e-7),
+			"limit":   Const(1e21),
+			"small":   Const(float32(0.5)),
+			"offset":  Const(-3),
+			"enabled": Const(true),
+			"url":     "url",
+		},
+	}, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{\n" +
+		"  MyApp: {\n" +
+		"    enabled: true\n" +
+		"    huge: 1.5e+300\n" +
+		"    large: 100000000000000000000\n" +
+		"    limit: 1e+21\n" +
+		"    offset: -3\n" +
+		"    port: 8080\n" +
+		"    quote: \"say \\\"hi\\\" \\\\ bye\"\n" +
+		"    ratio: 0.1\n" +
+		"    small: 0.5\n" +
+		"    stage: \"production\"\n" +
+		"    tiny: -1e-7\n" +
+		"    url: string\n" +
+		"  }\n" +
+		"}"
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	result, err = InferTypes(map[string]interface{}{
+		"port":  8080,
+		"stage": "dev",
+		"flags": []interface{}{true, false},
+	}, TypeOptions{PreferLiterals: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "{\n  flags: (true | false)[]\n  port: 8080\n  stage: \"dev\"\n}"
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	_, err = InferTypes(map[string]interface{}{"config": Const([]string{"a"})}, TypeOptions{})
+	if err == nil || err.Error() != "config: []string cannot be a literal type" {
+		t.Errorf("Expected literal type error, got %v", err)
+	}
+}
+
+func TestMultilineLiteral(t *testing.T) {
+	result, err := InferTypes(map[string]interface{}{
+		"MyApi": map[string]interface{}{
+			"routes": map[string]interface{}{
+				"config": Literal(`
+					{
+						port: number
+
+						host: string
+					}
+				`),
+				"mapped": Literal("{\n  [K in Keys]: string\n}\n"),
+			},
+		},
+	}, TypeOptions{FieldTerminator: ";"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{\n" +
+		"  MyApi: {\n" +
+		"    routes: {\n" +
+		"      config: {\n" +
+		"        port: number\n" +
+		"\n" +
+		"        host: string\n" +
+		"      }\n" +
+		"      mapped: {\n" +
+		"        [K in Keys]: string\n" +
+		"      }\n" +
+		"    }\n" +
+		"  }\n" +
+		"}"
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func BenchmarkInferTypesFlatWide(b *testing.B) {
+	input := map[string]interface{}{}
+	for i := 0; i < 3000; i++ {
+		input[fmt.Sprintf("Resource%d", i)] = map[string]interface{}{
+			"type": "sst.aws.Function",
+			"name": "name",
+			"arn":  "arn",
+			"url":  "url",
+		}
+	}
+	benchmarkInferTypes(b, input)
+}
+
+func BenchmarkInferTypesDeepNarrow(b *testing.B) {
+	input := map[string]interface{}{"value": "leaf"}
+	for i := 0; i < 200; i++ {
+		input = map[string]interface{}{"nested": input, "depth": i}
+	}
+	benchmarkInferTypes(b, input)
+}
+
+func BenchmarkInferTypesMixed(b *testing.B) {
+	input := syntheticResources(500)
+	for i := 0; i < 500; i++ {
+		input[fmt.Sprintf("Api%d", i)] = map[string]interface{}{
+			"type": "sst.aws.ApiGatewayV2",
+			"url":  Optional("url"),
+			"routes": []interface{}{
+				map[string]interface{}{"path": "/", "auth": nil},
+				map[string]interface{}{"path": "/users", "auth": map[string]interface{}{"type": "iam"}},
+			},
+			"tags": map[string]string{"env": "dev"},
+		}
+	}
+	benchmarkInferTypes(b, input)
+}
+
+func benchmarkInferTypes(b *testing.B, input map[string]interface{}) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		InferTypes(input, TypeOptions{})
+	}
+}
+
+func TestPointers(t *testing.T) {
+	name := "bucket"
+	port := 8080
+	public := true
+	config := map[string]interface{}{"memory": 1024}
+	var nilConfig *map[string]interface{}
+	result, err := InferTypes(map[string]interface{}{
+		"MyBucket": map[string]interface{}{
+			"type":   &[]string{"sst.aws.Bucket"}[0],
+			"name":   &name,
+			"port":   &port,
+			"public": &public,
+			"config": &config,
+			"pp":     &[]*int{&port}[0],
+		},
+		"Nil": map[string]interface{}{
+			"name":   (*string)(nil),
+			"port":   (*int)(nil),
+			"public": (*bool)(nil),
+			"config": nilConfig,
+		},
+	}, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{\n" +
+		"  MyBucket: {\n" +
+		"    config: {\n" +
+		"      memory: number\n" +
+		"    }\n" +
+		"    name: string\n" +
+		"    port: number\n" +
+		"    pp: number\n" +
+		"    public: boolean\n" +
+		"    type: \"sst.aws.Bucket\"\n" +
+		"  }\n" +
+		"  Nil: {\n" +
+		"    config?: unknown\n" +
+		"    name?: string\n" +
+		"    port?: number\n" +
+		"    public?: boolean\n" +
+		"  }\n" +
+		"}"
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	policy := `{
+		"Version": "2012-10-17",
+		"Statement": [
+			{"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": "*"},
+			{"Effect": "Deny", "Action": ["s3:PutObject"], "Resource": "*", "Sid": "deny"}
+		],
+		"MaxAge": 3600
+	}`
+	input := map[string]interface{}{
+		"MyBucket": map[string]interface{}{
+			"policy":  JSON(policy),
+			"raw":     JSON(json.RawMessage(`[1, 2]`)),
+			"enabled": JSON([]byte("true")),
+		},
+	}
+	result, err := InferTypes(input, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := strings.Join([]string{
+		"{",
+		"  MyBucket: {",
+		"    enabled: boolean",
+		"    policy: {",
+		"      MaxAge: number",
+		"      Statement: {",
+		"        Action: string[]",
+		"        Effect: string",
+		"        Resource: string",
+		"        Sid?: string",
+		"      }[]",
+		"      Version: string",
+		"    }",
+		"    raw: number[]",
+		"  }",
+		"}",
+	}, "\n")
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	python, err := GeneratePython(map[string]interface{}{"max_age": JSON(`3600`), "ratio": JSON(`0.5`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(python, "max_age: int\n") || !strings.Contains(python, "ratio: float\n") {
+		t.Errorf("Expected int and float, got %v", python)
+	}
+}
+
+func TestJSONInvalid(t *testing.T) {
+	input := map[string]interface{}{"policy": JSON(`{"Version": `)}
+	if _, err := InferTypes(input, TypeOptions{}); err == nil || !strings.Contains(err.Error(), "policy: invalid JSON") {
+		t.Errorf("Expected invalid JSON error, got %v", err)
+	}
+
+	var warnings []string
+	result, err := InferTypes(input, TypeOptions{
+		Lenient: true,
+		Warn:    func(warning string) { warnings = append(warnings, warning) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "{\n  policy: string\n}"; result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("Expected 1 warning, got %v", warnings)
+	}
+}
+
+func TestKeyPriority(t *testing.T) {
+	ordered := NewOrderedMap()
+	ordered.Set("url", "url")
+	ordered.Set("type", "queue")
+	input := map[string]interface{}{
+		"MyBucket": map[string]interface{}{
+			"arn":    "arn",
+			"bucket": "bucket",
+			"name":   "name",
+			"type":   "sst.aws.Bucket",
+			"cors": map[string]interface{}{
+				"origins": []string{"*"},
+				"name":    "cors",
+				"type":    "cors",
+			},
+		},
+		"Ordered": ordered,
+	}
+	opts := TypeOptions{KeyPriority: []string{"type", "name", "arn", "url", "missing"}}
+	expected := strings.Join([]string{
+		"{",
+		"  MyBucket: {",
+		`    type: "sst.aws.Bucket"`,
+		"    name: string",
+		"    arn: string",
+		"    bucket: string",
+		"    cors: {",
+		`      type: "cors"`,
+		"      name: string",
+		"      origins: string[]",
+		"    }",
+		"  }",
+		"  Ordered: {",
+		"    url: string",
+		`    type: "queue"`,
+		"  }",
+		"}",
+	}, "\n")
+	for i := 0; i < 10; i++ {
+		result, err := InferTypes(input, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != expected {
+			t.Fatalf("Expected %v, got %v", expected, result)
+		}
+	}
+}
+
+func TestFallbackType(t *testing.T) {
+	input := map[string]interface{}{"handler": func() {}}
+	for _, fallback := range []string{"unknown", "any"} {
+		var warnings []string
+		result, err := InferTypes(input, TypeOptions{
+			FallbackType: fallback,
+			Warn:         func(warning string) { warnings = append(warnings, warning) },
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := "{\n  handler: " + fallback + "\n}"; result != expected {
+			t.Errorf("Expected %v, got %v", expected, result)
+		}
+		if len(warnings) != 1 {
+			t.Errorf("Expected 1 warning, got %v", warnings)
+		}
+	}
+	if _, err := InferTypes(input, TypeOptions{FallbackType: "error", Lenient: true}); err == nil {
+		t.Errorf("Expected error fallback to fail")
+	}
+	if _, err := InferTypes(input, TypeOptions{}); err == nil {
+		t.Errorf("Expected default to fail")
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	input := map[string]interface{}{
+		"Legacy": map[string]interface{}{
+			"config": map[string]interface{}{"type": "v1", "token": Secret("token")},
+		},
+		"Rules": []interface{}{
+			map[string]interface{}{"action": "allow", "type": "ip"},
+		},
+		"Tags": []string{"a"},
+	}
+	result := InferResult(input, TypeOptions{
+		Overrides: map[string]string{
+			"Legacy.config":  "any",
+			"Rules[].action": `"allow" | "deny"`,
+			"Tags[]":         "`tag-${string}`",
+		},
+	})
+	expected := strings.Join([]string{
+		"{",
+		"  Legacy: {",
+		"    config: any",
+		"  }",
+		"  Rules: {",
+		`    action: "allow" | "deny"`,
+		`    type: "ip"`,
+		"  }[]",
+		"  Tags: `tag-${string}`[]",
+		"}",
+	}, "\n")
+	if result.Output != expected {
+		t.Errorf("Expected %v, got %v", expected, result.Output)
+	}
+	diagnostics := []Diagnostic{
+		{Severity: SeverityInfo, Path: "Legacy.config", Message: "type overridden with any"},
+		{Severity: SeverityInfo, Path: "Rules[].action", Message: `type overridden with "allow" | "deny"`},
+		{Severity: SeverityInfo, Path: "Tags[]", Message: "type overridden with `tag-${string}`"},
+	}
+	if !reflect.DeepEqual(result.Diagnostics, diagnostics) {
+		t.Errorf("Expected %v, got %v", diagnostics, result.Diagnostics)
+	}
+
+	if _, err := InferTypes(input, TypeOptions{Overrides: map[string]string{"Legacy.config": ""}}); err == nil || err.Error() != "Legacy.config: override is empty" {
+		t.Errorf("Expected empty override error, got %v", err)
+	}
+}
+
+func TestNumberTypes(t *testing.T) {
+	input := map[string]interface{}{
+		"count":   int64(3),
+		"ratio":   3.5,
+		"whole":   2.0,
+		"integer": json.Number("3"),
+		"decimal": json.Number("3.5"),
+		"mixed":   []interface{}{1, 2.5},
+	}
+	expected := map[string]NumberType{
+		"count":   {Integer: true},
+		"ratio":   {},
+		"whole":   {},
+		"integer": {Integer: true},
+		"decimal": {},
+		"mixed":   {},
+	}
+	inferred, err := Infer(input, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range inferred.(ObjectType).Fields {
+		number := field.Type
+		if array, ok := number.(ArrayType); ok {
+			number = array.Element
+		}
+		if number != expected[field.Key] {
+			t.Errorf("%s: Expected %v, got %v", field.Key, expected[field.Key], number)
+		}
+	}
+
+	result, _ := InferTypes(input, TypeOptions{})
+	if strings.Contains(result, "Integer") || strings.Count(result, "number") != 6 {
+		t.Errorf("Expected number by default, got %v", result)
+	}
+	result, _ = InferTypes(input, TypeOptions{IntegerType: "Integer", FloatType: "Float"})
+	for _, line := range []string{"count: Integer", "ratio: Float", "whole: Float", "integer: Integer", "decimal: Float", "mixed: Float[]"} {
+		if !strings.Contains(result, line) {
+			t.Errorf("Expected %v, got %v", line, result)
+		}
+	}
+
+	generated, err := GenerateGo(input, "resource")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"Count   int64 ", "Ratio   float64 ", "Whole   float64 ", "Integer int64 ", "Decimal float64 ", "Mixed   []float64 "} {
+		if !strings.Contains(generated, line) {
+			t.Errorf("Expected %v, got %v", line, generated)
+		}
+	}
+}
+
+func TestSanitizeIdentifier(t *testing.T) {
+	examples := map[string]string{
+		"my-api":      "MyApi",
+		"my.bucket":   "MyBucket",
+		"1st-queue":   "_1stQueue",
+		"données":     "Données",
+		"élan_vital":  "ÉlanVital",
+		"---":         "_",
+		"MyFunction":  "MyFunction",
+		"snake_case1": "SnakeCase1",
+	}
+	for key, expected := range examples {
+		if result := SanitizeIdentifier(key); result != expected {
+			t.Errorf("%s: Expected %v, got %v", key, expected, result)
+		}
+	}
+}
+
+func TestInferInterfacesIdentifiers(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		input := map[string]interface{}{
+			"my-api": map[string]interface{}{"url": "url"},
+			"my_api": map[string]interface{}{"url": "url"},
+			"MyApi":  map[string]interface{}{"url": "url"},
+			"2fa":    map[string]interface{}{"secret": "secret"},
+			"!!!":    map[string]interface{}{"value": "value"},
+		}
+		result := InferInterfacesResult(input, TypeOptions{})
+		expected := map[string]string{
+			"!!!":    "_",
+			"2fa":    "_2fa",
+			"MyApi":  "MyApi",
+			"my-api": "MyApi_2",
+			"my_api": "MyApi_3",
+		}
+		if !reflect.DeepEqual(result.Identifiers, expected) {
+			t.Fatalf("Expected %v, got %v", expected, result.Identifiers)
+		}
+		if !strings.Contains(result.Output, "  \"my-api\": MyApi_2\n") {
+			t.Errorf("Expected reference to MyApi_2, got %v", result.Output)
+		}
+	}
+}
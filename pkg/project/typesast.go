@@ -0,0 +1,388 @@
+package project
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// Type is a node of the TypeScript type tree produced by Infer.
+type Type interface {
+	isType()
+}
+
+// PrimitiveType is a built-in type such as string, number or unknown.
+type PrimitiveType string
+
+// NumberType is a number inferred from a value, which records whether that
+// value was an integer. Whole floats like 2.0 are still floats.
+type NumberType struct {
+	Integer bool
+}
+
+// LiteralType is a raw TypeScript fragment, like a string literal or a
+// caller supplied Literal.
+type LiteralType struct {
+	Value string
+	// Constant is set for the literal type of a value, like a discriminator
+	// or a Const, as opposed to a fragment given verbatim.
+	Constant bool
+}
+
+// ReferenceType refers to a named interface by name.
+type ReferenceType struct {
+	Name string
+}
+
+type ObjectType struct {
+	Fields []Field
+}
+
+type Field struct {
+	Key         string
+	Optional    bool
+	Description string
+	// Type is nil for values that inferTypes silently skips.
+	Type Type
+}
+
+type ArrayType struct {
+	Element Type
+}
+
+// TupleType is a fixed-length array typed per position.
+type TupleType struct {
+	Elements []TupleElement
+}
+
+type TupleElement struct {
+	Type     Type
+	Optional bool
+}
+
+type UnionType struct {
+	Members []Type
+}
+
+// RecordType maps keys to values, rendered as Record<Key, Value> or, when
+// Index is set, as an index signature.
+type RecordType struct {
+	Key   Type
+	Value Type
+	Index bool
+}
+
+func (PrimitiveType) isType() {}
+func (NumberType) isType()    {}
+func (LiteralType) isType()   {}
+func (ReferenceType) isType() {}
+func (ObjectType) isType()    {}
+func (ArrayType) isType()     {}
+func (TupleType) isType()     {}
+func (UnionType) isType()     {}
+func (RecordType) isType()    {}
+
+// RenderType renders t the way InferTypes renders the input it was inferred
+// from.
+func RenderType(t Type, opts TypeOptions) string {
+	var builder strings.Builder
+	streamType(&builder, t, opts, "")
+	return builder.String()
+}
+
+// streamType writes t to w through a buffer, stopping at the first failed
+// write. Builders are sized up front and written to directly.
+func streamType(w io.Writer, t Type, opts TypeOptions, indent string) error {
+	if builder, ok := w.(*strings.Builder); ok {
+		builder.Grow(estimateSize(t))
+		tw := &typeWriter{w: builder, opts: opts}
+		tw.write(t, indent)
+		if opts.TrailingNewline {
+			tw.string("\n")
+		}
+		return nil
+	}
+	buffered := bufio.NewWriter(w)
+	tw := &typeWriter{w: buffered, opts: opts}
+	tw.write(t, indent)
+	if opts.TrailingNewline {
+		tw.string("\n")
+	}
+	if tw.err != nil {
+		return tw.err
+	}
+	return buffered.Flush()
+}
+
+type typeWriter struct {
+	w    io.Writer
+	opts TypeOptions
+	err  error
+	// output is set while writing inside an Output<T>.
+	output bool
+	// inner caches the indent of the properties inside each indent.
+	inner map[string]string
+}
+
+func (w *typeWriter) string(value string) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = io.WriteString(w.w, value)
+}
+
+func (w *typeWriter) write(t Type, indent string) {
+	switch t := t.(type) {
+	case PrimitiveType:
+		w.string(string(t))
+	case NumberType:
+		switch {
+		case t.Integer && w.opts.IntegerType != "":
+			w.string(w.opts.IntegerType)
+		case !t.Integer && w.opts.FloatType != "":
+			w.string(w.opts.FloatType)
+		default:
+			w.string("number")
+		}
+	case LiteralType:
+		if !strings.Contains(t.Value, "\n") {
+			w.string(t.Value)
+			return
+		}
+		// Continuation lines of multi-line literals are indented to the
+		// property they belong to, with leading tabs as indent levels.
+		for i, line := range strings.Split(t.Value, "\n") {
+			if i > 0 {
+				w.string("\n")
+				if line != "" {
+					tabs := len(line) - len(strings.TrimLeft(line, "\t"))
+					w.string(indent + strings.Repeat(w.indent(), tabs))
+					line = line[tabs:]
+				}
+			}
+			w.string(line)
+		}
+	case ReferenceType:
+		w.string(t.Name)
+	case ObjectType:
+		w.object(t, indent)
+	case ArrayType:
+		if needsParens(t.Element) {
+			w.string("(")
+			w.write(t.Element, indent)
+			w.string(")")
+		} else {
+			w.write(t.Element, indent)
+		}
+		w.string("[]")
+	case TupleType:
+		w.string("[")
+		for i, element := range t.Elements {
+			if i > 0 {
+				w.string(", ")
+			}
+			if element.Optional && needsParens(element.Type) {
+				w.string("(")
+				w.write(element.Type, indent)
+				w.string(")")
+			} else {
+				w.write(element.Type, indent)
+			}
+			if element.Optional {
+				w.string("?")
+			}
+		}
+		w.string("]")
+	case UnionType:
+		for i, member := range t.Members {
+			if i > 0 {
+				w.string(" | ")
+			}
+			w.write(member, indent)
+		}
+	case RecordType:
+		if t.Index {
+			w.string("{ [key: ")
+			w.write(t.Key, indent)
+			w.string("]: ")
+			w.write(t.Value, indent)
+			w.string(" }")
+			return
+		}
+		w.string("Record<")
+		w.write(t.Key, indent)
+		w.string(", ")
+		w.write(t.Value, indent)
+		w.string(">")
+	}
+}
+
+func (w *typeWriter) object(t ObjectType, indent string) {
+	inner, ok := w.inner[indent]
+	if !ok {
+		if w.inner == nil {
+			w.inner = map[string]string{}
+		}
+		inner = indent + w.indent()
+		w.inner[indent] = inner
+	}
+	w.string("{\n")
+	for _, field := range t.Fields {
+		if w.err != nil {
+			return
+		}
+		w.comment(field.Description, inner)
+		w.string(inner)
+		if w.opts.Readonly {
+			w.string("readonly ")
+		}
+		w.string(propertyKey(field.Key))
+		if field.Optional {
+			w.string("?: ")
+		} else {
+			w.string(": ")
+		}
+		if w.wrapOutput(field.Type) {
+			w.output = true
+			w.string("Output<")
+			w.write(field.Type, inner)
+			w.string(">")
+			w.output = false
+		} else {
+			w.write(field.Type, inner)
+		}
+		if w.opts.TerminateBlocks || !multiline(field.Type) {
+			w.string(w.opts.FieldTerminator)
+		}
+		w.string("\n")
+	}
+	w.string(indent + "}")
+}
+
+// comment writes description as a JSDoc block at indent.
+func (w *typeWriter) comment(description string, indent string) {
+	if description == "" {
+		return
+	}
+	description = strings.ReplaceAll(description, "*/", "*\\/")
+	if rest, ok := strings.CutPrefix(description, "Deprecated:"); ok {
+		description = "@deprecated " + strings.TrimLeft(rest, " ")
+	}
+	lines := strings.Split(strings.TrimRight(description, "\n"), "\n")
+	if len(lines) == 1 {
+		w.string(indent + "/** " + lines[0] + " */\n")
+		return
+	}
+	w.string(indent + "/**\n")
+	for _, line := range lines {
+		w.string(strings.TrimRight(indent+" * "+line, " ") + "\n")
+	}
+	w.string(indent + " */\n")
+}
+
+// wrapOutput reports whether a property of type t is wrapped in Output<T>.
+// Literals given verbatim are written as given and arrays are wrapped like
+// their elements.
+func (w *typeWriter) wrapOutput(t Type) bool {
+	if !w.opts.Outputs || w.output {
+		return false
+	}
+	switch t := t.(type) {
+	case nil:
+		return false
+	case LiteralType:
+		return t.Constant
+	case ObjectType, ReferenceType:
+		return w.opts.OutputObjects
+	case ArrayType:
+		return w.wrapOutput(t.Element)
+	case UnionType:
+		for _, member := range t.Members {
+			if w.wrapOutput(member) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (w *typeWriter) indent() string {
+	if w.opts.Indent != "" {
+		return w.opts.Indent
+	}
+	return "  "
+}
+
+// estimateSize guesses the rendered length of t, to size buffers once.
+func estimateSize(t Type) int {
+	switch t := t.(type) {
+	case ObjectType:
+		size := 2
+		for _, field := range t.Fields {
+			size += 16 + len(field.Key) + estimateSize(field.Type)
+		}
+		return size
+	case ArrayType:
+		return 2 + estimateSize(t.Element)
+	case UnionType:
+		size := 0
+		for _, member := range t.Members {
+			size += 3 + estimateSize(member)
+		}
+		return size
+	case LiteralType:
+		return len(t.Value)
+	}
+	return 8
+}
+
+func multiline(t Type) bool {
+	switch t := t.(type) {
+	case ObjectType:
+		return true
+	case LiteralType:
+		return strings.Contains(t.Value, "\n")
+	case ArrayType:
+		return multiline(t.Element)
+	case TupleType:
+		for _, element := range t.Elements {
+			if multiline(element.Type) {
+				return true
+			}
+		}
+	case UnionType:
+		for _, member := range t.Members {
+			if multiline(member) {
+				return true
+			}
+		}
+	case RecordType:
+		return multiline(t.Value)
+	}
+	return false
+}
+
+// needsParens reports whether t has to be parenthesized to be used as an
+// array element.
+func needsParens(t Type) bool {
+	switch t := t.(type) {
+	case UnionType:
+		return len(t.Members) > 1
+	case LiteralType:
+		depth := 0
+		for _, r := range t.Value {
+			switch r {
+			case '{', '(', '[', '<':
+				depth++
+			case '}', ')', ']', '>':
+				depth--
+			case '|':
+				if depth == 0 {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package project
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func roundTripInput() map[string]interface{} {
+	shared := map[string]interface{}{"url": "url"}
+	node := map[string]interface{}{"name": "node"}
+	node["parent"] = node
+	return map[string]interface{}{
+		"MyBucket": map[string]interface{}{
+			"type":   "sst.aws.Bucket",
+			"name":   "bucket",
+			"public": true,
+			"size":   int64(10),
+			"ratio":  float32(0.5),
+		},
+		"my-api": map[string]interface{}{
+			"type":    "sst.aws.ApiGatewayV2",
+			"url":     "url",
+			"routes":  []string{"GET /", "POST /"},
+			"matrix":  [][]int{{1}, {2}},
+			"empty":   []interface{}{},
+			"mixed":   []interface{}{"a", 1, nil},
+			"binding": Literal(`import("@cloudflare/workers-types").R2Bucket`),
+			"levels":  Union([]string{"debug", "info"}),
+			"shared":  shared,
+			"again":   shared,
+			"rules": []interface{}{
+				map[string]interface{}{"type": "allow", "id": "a", "priority": 1},
+				map[string]interface{}{"type": "deny", "id": 2, "match": map[string]interface{}{"path": "/"}},
+			},
+		},
+		"Optional": map[string]interface{}{
+			"missing": nil,
+			"maybe":   Optional("value"),
+			"nested":  Optional(map[string]interface{}{"port": 8080}),
+			"pointer": (*string)(nil),
+		},
+		"Records": map[string]interface{}{
+			"environment": map[string]string{"A": "a"},
+			"ports":       map[string]int{},
+			"tags":        Dynamic(map[string]interface{}{"team": "core", "cost": 1}),
+			"none":        Dynamic(map[string]interface{}{}),
+		},
+		"Builtins": map[string]interface{}{
+			"created": time.Unix(0, 0),
+			"count":   json.Number("3"),
+			"blob":    []byte("data"),
+			"custom":  cidr{},
+		},
+		"Node":     node,
+		"auth.key": "value",
+		"default":  "value",
+		"0":        1,
+	}
+}
+
+var roundTripOptions = map[string]TypeOptions{
+	"roundtrip_default.golden": {
+		Types: map[reflect.Type]string{reflect.TypeOf(cidr{}): "`${string}/${number}`"},
+	},
+	"roundtrip_formatted.golden": {
+		Types:           map[reflect.Type]string{reflect.TypeOf(cidr{}): "`${string}/${number}`"},
+		Less:            TypeFirst,
+		Indent:          "\t",
+		FieldTerminator: ";",
+		TerminateBlocks: true,
+		TrailingNewline: true,
+		Discriminators:  []string{"type", "name"},
+		NilType:         "undefined",
+	},
+}
+
+func TestRoundTripGolden(t *testing.T) {
+	for file, opts := range roundTripOptions {
+		opts.Lenient = true
+		result, err := InferInterfaces(roundTripInput(), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectGolden(t, "interfaces_"+file, result)
+		input := roundTripInput()
+		delete(input, "Node")
+		result, err = InferTypes(input, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectGolden(t, file, result)
+	}
+}
+
+func TestRoundTripLegacyGolden(t *testing.T) {
+	input := roundTripInput()
+	delete(input, "Node")
+	input["handler"] = func() {}
+	input["list"] = []interface{}{make(chan int)}
+	expectGolden(t, "roundtrip_legacy.golden", inferTypes(input, "  "))
+}
+
+func TestInfer(t *testing.T) {
+	input := map[string]interface{}{
+		"MyBucket": map[string]interface{}{
+			"type": "sst.aws.Bucket",
+			"tags": []interface{}{"a", 1},
+		},
+	}
+	result, err := Infer(input, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := ObjectType{Fields: []Field{
+		{Key: "MyBucket", Type: ObjectType{Fields: []Field{
+			{Key: "tags", Type: ArrayType{Element: UnionType{Members: []Type{PrimitiveType("string"), NumberType{Integer: true}}}}},
+			{Key: "type", Type: LiteralType{Value: `"sst.aws.Bucket"`, Constant: true}},
+		}}},
+	}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, result)
+	}
+
+	types, _ := InferTypes(input, TypeOptions{})
+	if rendered := RenderType(result, TypeOptions{}); rendered != types {
+		t.Errorf("Expected %v, got %v", types, rendered)
+	}
+}
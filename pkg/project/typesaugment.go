@@ -0,0 +1,98 @@
+package project
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// typed is a value whose type is already known, like a property parsed from
+// an augmentation file.
+type typed struct {
+	t Type
+}
+
+// ReadAugmentations reads the Augmentations in path, either a JSON document
+// whose values are inferred like any input or a TypeScript object type as
+// rendered by RenderType.
+func ReadAugmentations(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Ext(path) == ".json" {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		var result map[string]interface{}
+		if err := decoder.Decode(&result); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return result, nil
+	}
+	t, err := ParseType(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	object, ok := t.(ObjectType)
+	if !ok {
+		return nil, fmt.Errorf("%s: expected an object type", path)
+	}
+	return augmentationValues(object), nil
+}
+
+// augmentationValues turns object back into input, keeping nested objects as
+// maps so that they are merged key by key.
+func augmentationValues(object ObjectType) map[string]interface{} {
+	result := make(map[string]interface{}, len(object.Fields))
+	for _, field := range object.Fields {
+		var value interface{} = typed{t: field.Type}
+		if nested, ok := field.Type.(ObjectType); ok {
+			value = augmentationValues(nested)
+		}
+		if field.Optional {
+			value = Optional(value)
+		}
+		result[field.Key] = value
+	}
+	return result
+}
+
+// augment returns input with the keys of augmentations merged into it,
+// noting every key they supply. input itself is left untouched.
+func (g *typeGenerator) augment(input, augmentations map[string]interface{}, path string) map[string]interface{} {
+	if len(augmentations) == 0 {
+		return input
+	}
+	result := make(map[string]interface{}, len(input)+len(augmentations))
+	for key, value := range input {
+		result[key] = value
+	}
+	for _, key := range sortedKeys(augmentations, nil) {
+		augmentation := augmentations[key]
+		keyPath := joinPath(path, key)
+		existing, ok := input[key]
+		if !ok {
+			result[key] = augmentation
+			g.diagnostics = append(g.diagnostics, Diagnostic{
+				Severity: SeverityInfo,
+				Path:     keyPath,
+				Message:  "supplied by augmentation",
+			})
+			continue
+		}
+		generated, generatedObject := existing.(map[string]interface{})
+		augmented, augmentedObject := augmentation.(map[string]interface{})
+		if generatedObject && augmentedObject {
+			result[key] = g.augment(generated, augmented, keyPath)
+			continue
+		}
+		if reflect.DeepEqual(existing, augmentation) {
+			continue
+		}
+		g.report(keyPath, fmt.Errorf("%s: augmentation conflicts with the generated property", keyPath), !g.opts.RejectAugmentationConflicts)
+	}
+	return result
+}
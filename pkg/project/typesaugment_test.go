@@ -0,0 +1,126 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var augmentInput = map[string]interface{}{
+	"MyBucket": map[string]interface{}{"name": "bucket"},
+	"Stage":    "dev",
+}
+
+func TestAugmentations(t *testing.T) {
+	result := InferResult(augmentInput, TypeOptions{
+		Augmentations: map[string]interface{}{
+			"LEGACY_URL": "url",
+			"MyBucket":   map[string]interface{}{"arn": Literal("`arn:${string}`")},
+		},
+	})
+	expected := strings.Join([]string{
+		"{",
+		"  LEGACY_URL: string",
+		"  MyBucket: {",
+		"    arn: `arn:${string}`",
+		"    name: string",
+		"  }",
+		"  Stage: string",
+		"}",
+	}, "\n")
+	if result.Output != expected {
+		t.Errorf("Expected %v, got %v", expected, result.Output)
+	}
+	diagnostics := []Diagnostic{
+		{Severity: SeverityInfo, Path: "LEGACY_URL", Message: "supplied by augmentation"},
+		{Severity: SeverityInfo, Path: "MyBucket.arn", Message: "supplied by augmentation"},
+	}
+	if !reflect.DeepEqual(result.Diagnostics, diagnostics) {
+		t.Errorf("Expected %v, got %v", diagnostics, result.Diagnostics)
+	}
+	interfaces, err := InferInterfaces(augmentInput, TypeOptions{
+		Augmentations: map[string]interface{}{"MyBucket": map[string]interface{}{"arn": "arn"}},
+	})
+	if err != nil || !strings.Contains(interfaces, "export interface MyBucket {\n  arn: string\n  name: string\n}") {
+		t.Errorf("Expected augmented interface, got %v %v", interfaces, err)
+	}
+	if _, ok := augmentInput["LEGACY_URL"]; ok {
+		t.Errorf("Expected input to be left untouched")
+	}
+}
+
+func TestAugmentationsEntryPoints(t *testing.T) {
+	opts := TypeOptions{
+		Augmentations: map[string]interface{}{
+			"LEGACY_URL": "url",
+			"MyBucket":   map[string]interface{}{"arn": "arn"},
+		},
+		Namespaces: map[string]string{"MyBucket": "aws"},
+	}
+	result, err := InferNamespaces(augmentInput, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"    MyBucket: {\n      arn: string\n      name: string\n    }",
+		"    LEGACY_URL: string\n    Stage: string",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected %v in %v", expected, result)
+		}
+	}
+	secrets, _, err := InferSecrets(augmentInput, opts)
+	if err != nil || !strings.Contains(secrets, "LEGACY_URL: string") {
+		t.Errorf("Expected augmented secrets output, got %v %v", secrets, err)
+	}
+}
+
+func TestAugmentationConflicts(t *testing.T) {
+	opts := TypeOptions{Augmentations: map[string]interface{}{"Stage": 1}}
+	result := InferResult(augmentInput, opts)
+	if !strings.Contains(result.Output, "Stage: string") {
+		t.Errorf("Expected generated type to win, got %v", result.Output)
+	}
+	if err := result.Err(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(result.Diagnostics) != 1 || result.Diagnostics[0].Severity != SeverityWarning {
+		t.Errorf("Expected conflict warning, got %v", result.Diagnostics)
+	}
+
+	opts.RejectAugmentationConflicts = true
+	if _, err := InferTypes(augmentInput, opts); err == nil || err.Error() != "Stage: augmentation conflicts with the generated property" {
+		t.Errorf("Expected conflict error, got %v", err)
+	}
+}
+
+func TestReadAugmentations(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"augment.json": `{"MyBucket": {"region": "us-east-1"}, "PORT": 8080}`,
+		"augment.d.ts": "{\n  MyBucket: {\n    region: \"us-east-1\" | \"eu-west-1\"\n  }\n  PORT?: number\n}",
+	}
+	expected := map[string]string{
+		"augment.json": "    region: string\n",
+		"augment.d.ts": "    region: \"us-east-1\" | \"eu-west-1\"\n",
+	}
+	for file, content := range files {
+		path := filepath.Join(dir, file)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		augmentations, err := ReadAugmentations(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := InferTypes(augmentInput, TypeOptions{Augmentations: augmentations})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(result, expected[file]) || !strings.Contains(result, "    name: string\n") || !strings.Contains(result, "PORT") {
+			t.Errorf("%s: Expected augmented type, got %v", file, result)
+		}
+	}
+}
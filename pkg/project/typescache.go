@@ -0,0 +1,216 @@
+package project
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// TypeGenerator renders types like InferTypes and caches the output for the
+// most recently rendered inputs by their HashInput. Warnings are only
+// reported the first time an input is rendered.
+type TypeGenerator struct {
+	opts TypeOptions
+	size int
+
+	mu      sync.Mutex
+	entries *list.List
+	cache   map[string]*list.Element
+	hits    int
+}
+
+type cacheEntry struct {
+	hash   string
+	result string
+	err    error
+}
+
+// NewTypeGenerator returns a TypeGenerator that keeps the output of up to
+// size inputs.
+func NewTypeGenerator(opts TypeOptions, size int) *TypeGenerator {
+	return &TypeGenerator{
+		opts:    opts,
+		size:    size,
+		entries: list.New(),
+		cache:   map[string]*list.Element{},
+	}
+}
+
+func (g *TypeGenerator) InferTypes(input map[string]interface{}) (string, error) {
+	hash := HashInput(input)
+	g.mu.Lock()
+	if element, ok := g.cache[hash]; ok {
+		g.entries.MoveToFront(element)
+		g.hits++
+		entry := element.Value.(*cacheEntry)
+		g.mu.Unlock()
+		return entry.result, entry.err
+	}
+	g.mu.Unlock()
+
+	result, err := InferTypes(input, g.opts)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.cache[hash]; !ok && g.size > 0 {
+		g.cache[hash] = g.entries.PushFront(&cacheEntry{hash: hash, result: result, err: err})
+		for g.entries.Len() > g.size {
+			oldest := g.entries.Back()
+			g.entries.Remove(oldest)
+			delete(g.cache, oldest.Value.(*cacheEntry).hash)
+		}
+	}
+	return result, err
+}
+
+// HashInput returns a hex encoded SHA-256 hash of input that does not depend
+// on map iteration order. Wrappers like Literal and Optional are part of the
+// hash, so inputs that render differently hash differently.
+func HashInput(input map[string]interface{}) string {
+	h := &inputHasher{hash: sha256.New(), visiting: map[uintptr]int{}}
+	h.value(input)
+	return hex.EncodeToString(h.hash.Sum(nil))
+}
+
+type inputHasher struct {
+	hash    hash.Hash
+	scratch []byte
+	// visiting maps the maps being hashed to their depth, so cycles hash
+	// as a reference to where they point.
+	visiting map[uintptr]int
+}
+
+// write hashes every part prefixed with its length, so parts cannot run
+// into each other.
+func (h *inputHasher) write(parts ...string) {
+	for _, part := range parts {
+		h.scratch = strconv.AppendInt(h.scratch[:0], int64(len(part)), 10)
+		h.scratch = append(append(h.scratch, ':'), part...)
+		h.hash.Write(h.scratch)
+	}
+}
+
+func (h *inputHasher) value(value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		h.write("nil")
+		return
+	case string:
+		h.write("string", v)
+		return
+	case map[string]interface{}:
+		h.write("object", strconv.Itoa(len(v)))
+		if h.enter(v) {
+			defer h.leave(v)
+			for _, key := range sortedKeys(v, nil) {
+				h.write(key)
+				h.value(v[key])
+			}
+		}
+		return
+	case optional:
+		h.write("optional")
+		h.value(v.value)
+		return
+	case constant:
+		h.write("const")
+		h.value(v.value)
+		return
+	case dynamic:
+		h.write("dynamic")
+		h.value(v.value)
+		return
+	case secret:
+		h.write("secret", v.value)
+		return
+	case document:
+		h.write("json", string(v.raw))
+		return
+	case typed:
+		h.write("typed", RenderType(v.t, TypeOptions{}))
+		return
+	case *OrderedMap:
+		h.write("ordered", strconv.Itoa(len(v.keys)))
+		if h.enter(v.values) {
+			defer h.leave(v.values)
+			for _, key := range v.keys {
+				h.write(key)
+				h.value(v.values[key])
+			}
+		}
+		return
+	}
+	rv := reflect.ValueOf(value)
+	h.write(rv.Type().String())
+	switch rv.Kind() {
+	case reflect.Map:
+		h.write(strconv.Itoa(rv.Len()))
+		if !h.enter(value) {
+			return
+		}
+		defer h.leave(value)
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, key := range keys {
+			h.write(fmt.Sprint(key.Interface()))
+			h.value(rv.MapIndex(key).Interface())
+		}
+	case reflect.Slice, reflect.Array:
+		h.write(strconv.Itoa(rv.Len()))
+		for i := 0; i < rv.Len(); i++ {
+			h.value(rv.Index(i).Interface())
+		}
+	case reflect.Interface:
+		if rv.IsNil() {
+			h.write("nil")
+			return
+		}
+		h.value(rv.Elem().Interface())
+	case reflect.Pointer:
+		if rv.IsNil() {
+			h.write("nil")
+			return
+		}
+		if h.enter(value) {
+			defer h.leave(value)
+			h.value(rv.Elem().Interface())
+		}
+	case reflect.Struct:
+		if _, ok := value.(time.Time); ok {
+			h.write(fmt.Sprint(value))
+			return
+		}
+		// Structs are hashed as the object they are inferred as.
+		object := map[string]interface{}{}
+		(&typeGenerator{}).structFields(rv, object)
+		h.value(object)
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		// Only their type is stable.
+	default:
+		h.write(fmt.Sprint(value))
+	}
+}
+
+// enter marks the map value as being hashed. It returns false and hashes a
+// reference instead when the map is already being hashed.
+func (h *inputHasher) enter(value interface{}) bool {
+	pointer := reflect.ValueOf(value).Pointer()
+	if depth, ok := h.visiting[pointer]; ok && pointer != 0 {
+		h.write("cycle", strconv.Itoa(depth))
+		return false
+	}
+	h.visiting[pointer] = len(h.visiting)
+	return true
+}
+
+func (h *inputHasher) leave(value interface{}) {
+	delete(h.visiting, reflect.ValueOf(value).Pointer())
+}
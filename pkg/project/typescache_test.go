@@ -0,0 +1,92 @@
+package project
+
+import (
+	"testing"
+)
+
+func TestHashInput(t *testing.T) {
+	input := syntheticResources(10)
+	if HashInput(input) != HashInput(syntheticResources(10)) {
+		t.Errorf("Expected hash to be stable")
+	}
+	examples := map[string]interface{}{
+		"literal":  Literal("string"),
+		"optional": Optional("string"),
+		"value":    "other",
+	}
+	hashes := map[string]bool{HashInput(map[string]interface{}{"key": "string"}): true}
+	for name, value := range examples {
+		hash := HashInput(map[string]interface{}{"key": value})
+		if hashes[hash] {
+			t.Errorf("%s: Expected a distinct hash", name)
+		}
+		hashes[hash] = true
+	}
+
+	self := map[string]interface{}{"name": "self"}
+	self["self"] = self
+	if HashInput(self) == "" {
+		t.Errorf("Expected cyclic input to hash")
+	}
+
+	type node struct {
+		Value interface{} `json:"value"`
+		Next  *node       `json:"next"`
+	}
+	pointee := &node{Value: "string"}
+	structs := map[string]interface{}{"node": node{Next: pointee}}
+	before := HashInput(structs)
+	pointee.Value = 1
+	if HashInput(structs) == before {
+		t.Errorf("Expected changed pointee to change the hash")
+	}
+	pointee.Next = pointee
+	if HashInput(structs) == "" {
+		t.Errorf("Expected cyclic struct to hash")
+	}
+}
+
+func TestTypeGenerator(t *testing.T) {
+	g := NewTypeGenerator(TypeOptions{}, 2)
+	input := syntheticResources(3)
+	first, err := g.InferTypes(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := g.InferTypes(input)
+	if second != first || g.hits != 1 {
+		t.Errorf("Expected cached result, got %v hits", g.hits)
+	}
+
+	input["Resource1"].(map[string]interface{})["config"].(map[string]interface{})["memory"] = "1024"
+	changed, _ := g.InferTypes(input)
+	if changed == first || g.hits != 1 {
+		t.Errorf("Expected nested change to bust the cache, got %v hits", g.hits)
+	}
+
+	g.InferTypes(map[string]interface{}{"other": "value"})
+	g.InferTypes(syntheticResources(3))
+	if g.hits != 1 || g.entries.Len() != 2 {
+		t.Errorf("Expected least recently used entry to be evicted, got %v hits and %v entries", g.hits, g.entries.Len())
+	}
+}
+
+func BenchmarkTypeGeneratorCached(b *testing.B) {
+	input := syntheticResources(500)
+	g := NewTypeGenerator(TypeOptions{}, 8)
+	g.InferTypes(input)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.InferTypes(input)
+	}
+}
+
+func BenchmarkTypeGeneratorUncached(b *testing.B) {
+	input := syntheticResources(500)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		InferTypes(input, TypeOptions{})
+	}
+}
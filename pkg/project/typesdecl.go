@@ -0,0 +1,94 @@
+package project
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// DeclOptions configures GenerateDeclarationFile.
+type DeclOptions struct {
+	TypeOptions
+	// Banner is written into the header comment, followed by the hash of
+	// the rest of the file. Defaults to the banner written by
+	// WriteTypesFile.
+	Banner string
+	// Module wraps the root interface in declare module, augmenting that
+	// module instead of exporting a new interface.
+	Module string
+	// Imports are written after the header alongside the imports the
+	// rendered types need.
+	Imports []string
+}
+
+// GenerateDeclarationFile renders input as a complete .d.ts file: a banner
+// holding the hash of the content, imports, and the root interface, wrapped
+// in declare module when Module is set.
+func GenerateDeclarationFile(input map[string]interface{}, opts DeclOptions) (string, error) {
+	content, hash, err := declarationFile(input, opts)
+	return declarationHeader(opts.Banner, hash) + content, err
+}
+
+// declarationFile renders the declaration file for input without its
+// header, along with the hash the header records.
+func declarationFile(input map[string]interface{}, opts DeclOptions) (string, string, error) {
+	typeOpts := opts.TypeOptions
+	typeOpts.TrailingNewline = false
+	root := typeOpts.RootName
+	if root == "" {
+		root = "Resource"
+	}
+	g := &typeGenerator{
+		opts:         typeOpts,
+		discriminate: true,
+	}
+	t, err := g.root(input)
+
+	imports := map[string]bool{}
+	for _, line := range opts.Imports {
+		imports[line] = true
+	}
+	if typeOpts.Outputs {
+		imports[`import { Output } from "@pulumi/pulumi"`] = true
+	}
+	if opts.Module != "" {
+		imports["import "+quoteString(opts.Module)] = true
+	}
+	lines := make([]string, 0, len(imports))
+	for line := range imports {
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+
+	var builder strings.Builder
+	w := &typeWriter{w: &builder, opts: typeOpts}
+	for _, line := range lines {
+		w.string(line + "\n")
+	}
+	if len(lines) > 0 {
+		w.string("\n")
+	}
+	if opts.Module != "" {
+		indent := w.indent()
+		w.string("declare module " + quoteString(opts.Module) + " {\n")
+		w.string(indent + "export interface " + root + " ")
+		w.write(t, indent)
+		w.string("\n}\n\nexport {}\n")
+	} else {
+		w.string("export interface " + root + " ")
+		w.write(t, "")
+		w.string("\n")
+	}
+
+	content := builder.String()
+	hash := sha256.Sum256([]byte(content))
+	return content, hex.EncodeToString(hash[:]), err
+}
+
+func declarationHeader(banner string, hash string) string {
+	if banner == "" {
+		banner = defaultBanner
+	}
+	return "/* " + banner + " hash:" + hash + " */\n"
+}
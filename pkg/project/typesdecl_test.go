@@ -0,0 +1,48 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateDeclarationFile(t *testing.T) {
+	input := map[string]interface{}{
+		"MyBucket": map[string]interface{}{
+			"type": "sst.aws.Bucket",
+			"name": "bucket",
+		},
+		"MyQueue": map[string]interface{}{"url": "url"},
+	}
+	examples := map[string]DeclOptions{
+		"decl_interface.golden": {
+			TypeOptions: TypeOptions{Outputs: true},
+			Imports:     []string{`import type { Bucket } from "./bucket"`},
+		},
+		"decl_module.golden": {
+			Banner: "Generated by the test suite.",
+			Module: "sst",
+		},
+	}
+	for file, opts := range examples {
+		result, err := GenerateDeclarationFile(input, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := os.ReadFile(filepath.Join("testdata", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != string(expected) {
+			t.Errorf("%s: Expected %v, got %v", file, string(expected), result)
+		}
+
+		path := filepath.Join(t.TempDir(), "sst-env.d.ts")
+		if err := os.WriteFile(path, []byte(result), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if readTypesFileHash(path) == "" {
+			t.Errorf("%s: Expected hash to match content", file)
+		}
+	}
+}
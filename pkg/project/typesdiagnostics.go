@@ -0,0 +1,103 @@
+package project
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+type Severity int
+
+const (
+	SeverityWarning Severity = iota
+	SeverityError
+	// SeverityInfo notes where a property came from.
+	SeverityInfo
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityInfo:
+		return "info"
+	}
+	return "warning"
+}
+
+// Diagnostic is a problem or note found while inferring the value at Path.
+type Diagnostic struct {
+	Severity Severity
+	Path     string
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	if d.Path == "" {
+		return d.Severity.String() + ": " + d.Message
+	}
+	return d.Severity.String() + ": " + d.Path + ": " + d.Message
+}
+
+// Result is the outcome of InferResult or InferInterfacesResult.
+type Result struct {
+	Type        Type
+	Output      string
+	Diagnostics []Diagnostic
+	// Identifiers maps key paths to the interfaces they were declared as.
+	Identifiers map[string]string
+}
+
+// Err joins the error diagnostics of r, or returns nil if there are none.
+func (r Result) Err() error {
+	var errs []error
+	for _, diagnostic := range r.Diagnostics {
+		if diagnostic.Severity != SeverityError {
+			continue
+		}
+		if diagnostic.Path == "" {
+			errs = append(errs, errors.New(diagnostic.Message))
+		} else {
+			errs = append(errs, errors.New(diagnostic.Path+": "+diagnostic.Message))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// InferResult infers and renders input like InferTypes, reporting every
+// error and warning as a diagnostic instead of failing on the first kind.
+func InferResult(input map[string]interface{}, opts TypeOptions) Result {
+	g := &typeGenerator{
+		opts:         opts,
+		discriminate: true,
+	}
+	t, _ := g.root(input)
+	return Result{
+		Type:        t,
+		Output:      RenderType(t, opts),
+		Diagnostics: g.diagnostics,
+	}
+}
+
+// FormatDiagnostics renders diagnostics one per line, followed by a count of
+// errors and warnings.
+func FormatDiagnostics(diagnostics []Diagnostic) string {
+	if len(diagnostics) == 0 {
+		return ""
+	}
+	var builder strings.Builder
+	counts := map[Severity]int{}
+	for _, diagnostic := range diagnostics {
+		builder.WriteString(diagnostic.String() + "\n")
+		counts[diagnostic.Severity]++
+	}
+	builder.WriteString(plural(counts[SeverityError], "error") + ", " + plural(counts[SeverityWarning], "warning"))
+	return builder.String()
+}
+
+func plural(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return strconv.Itoa(count) + " " + noun + "s"
+}
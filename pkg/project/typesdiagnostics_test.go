@@ -0,0 +1,56 @@
+package project
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInferResult(t *testing.T) {
+	input := map[string]interface{}{
+		"MyApi": map[string]interface{}{
+			"handler": func() {},
+			"routes": map[string]interface{}{
+				"GET": map[string]interface{}{"path": "/"},
+			},
+		},
+		"MyBucket": map[string]interface{}{"name": "bucket", "Name": "bucket"},
+	}
+	opts := TypeOptions{
+		MaxDepth:           2,
+		CheckKeyCollisions: true,
+		Descriptions:       map[string]string{"MyQueue.url": "The queue URL"},
+	}
+	result := InferResult(input, opts)
+	expected := []Diagnostic{
+		{Severity: SeverityError, Path: "MyApi.handler", Message: "unsupported type func()"},
+		{Severity: SeverityWarning, Path: "MyApi.routes", Message: "truncated at depth 2"},
+		{Severity: SeverityWarning, Path: "MyBucket", Message: `keys "Name" and "name" collide`},
+		{Severity: SeverityWarning, Path: "MyQueue.url", Message: "description matches no property"},
+	}
+	if !reflect.DeepEqual(result.Diagnostics, expected) {
+		t.Errorf("Expected %v, got %v", expected, result.Diagnostics)
+	}
+	if !strings.Contains(result.Output, "routes: any") {
+		t.Errorf("Expected truncated output, got %v", result.Output)
+	}
+	if err := result.Err(); err == nil || err.Error() != "MyApi.handler: unsupported type func()" {
+		t.Errorf("Expected unsupported type error, got %v", err)
+	}
+	formatted := FormatDiagnostics(result.Diagnostics)
+	if !strings.HasPrefix(formatted, "error: MyApi.handler: unsupported type func()\nwarning: MyApi.routes: truncated at depth 2\n") ||
+		!strings.HasSuffix(formatted, "\n1 error, 3 warnings") {
+		t.Errorf("Expected formatted diagnostics, got %v", formatted)
+	}
+
+	opts.WarningsAsErrors = true
+	opts.Lenient = true
+	for _, diagnostic := range InferResult(input, opts).Diagnostics {
+		if diagnostic.Severity != SeverityError {
+			t.Errorf("Expected %v to be an error", diagnostic)
+		}
+	}
+	if _, err := InferTypes(input, opts); err == nil {
+		t.Errorf("Expected warnings to fail InferTypes")
+	}
+}
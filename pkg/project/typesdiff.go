@@ -0,0 +1,91 @@
+package project
+
+import "fmt"
+
+// Change is a difference between two type trees at a dotted key path.
+type Change struct {
+	Path string
+	// Old and New are the rendered types, empty for added and removed
+	// properties respectively.
+	Old string
+	New string
+}
+
+func (c Change) String() string {
+	switch {
+	case c.Old == "":
+		return c.Path + " added"
+	case c.New == "":
+		return c.Path + " removed"
+	}
+	return fmt.Sprintf("%s changed from %s to %s", c.Path, c.Old, c.New)
+}
+
+// Diff lists the properties added, removed or retyped between old and new,
+// descending into objects and arrays of objects.
+func Diff(old, new Type) []Change {
+	var changes []Change
+	diffTypes(old, new, "", &changes)
+	return changes
+}
+
+func diffTypes(old, new Type, path string, changes *[]Change) {
+	switch old := old.(type) {
+	case ObjectType:
+		if new, ok := new.(ObjectType); ok {
+			diffObjects(old, new, path, changes)
+			return
+		}
+	case ArrayType:
+		if new, ok := new.(ArrayType); ok {
+			diffTypes(old.Element, new.Element, path+"[]", changes)
+			return
+		}
+	}
+	if !sameType(old, new) {
+		*changes = append(*changes, Change{Path: path, Old: describeType(old), New: describeType(new)})
+	}
+}
+
+func diffObjects(old, new ObjectType, path string, changes *[]Change) {
+	fields := map[string]interface{}{}
+	oldFields := map[string]Field{}
+	newFields := map[string]Field{}
+	for _, field := range old.Fields {
+		oldFields[field.Key] = field
+		fields[field.Key] = nil
+	}
+	for _, field := range new.Fields {
+		newFields[field.Key] = field
+		fields[field.Key] = nil
+	}
+	for _, key := range sortedKeys(fields, nil) {
+		fieldPath := joinPath(path, key)
+		oldField, inOld := oldFields[key]
+		newField, inNew := newFields[key]
+		switch {
+		case !inOld:
+			*changes = append(*changes, Change{Path: fieldPath, New: describeField(newField)})
+		case !inNew:
+			*changes = append(*changes, Change{Path: fieldPath, Old: describeField(oldField)})
+		case oldField.Optional != newField.Optional:
+			*changes = append(*changes, Change{Path: fieldPath, Old: describeField(oldField), New: describeField(newField)})
+		default:
+			diffTypes(oldField.Type, newField.Type, fieldPath, changes)
+		}
+	}
+}
+
+func describeField(field Field) string {
+	if field.Optional {
+		return describeType(field.Type) + " (optional)"
+	}
+	return describeType(field.Type)
+}
+
+func describeType(t Type) string {
+	if _, ok := t.(ObjectType); ok {
+		return "object"
+	}
+	return RenderType(t, TypeOptions{})
+}
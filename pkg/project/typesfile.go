@@ -0,0 +1,127 @@
+package project
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const defaultBanner = "This file is auto-generated by SST. Do not edit."
+
+// typesFileOptions augment the sst module with the linked resources. Values
+// that cannot be typed become unknown rather than failing the write.
+var typesFileOptions = DeclOptions{
+	TypeOptions: TypeOptions{Lenient: true},
+	Module:      "sst",
+}
+
+// ErrLockTimeout is returned when the lock on a types file could not be
+// acquired in time.
+var ErrLockTimeout = errors.New("timed out waiting for lock")
+
+// WriteTypesFile writes the sst-env.d.ts declaration for input to path. The
+// file is left untouched when it already holds the same declaration, which
+// is detected through the hash embedded in its header.
+func WriteTypesFile(path string, input map[string]interface{}) (bool, error) {
+	return WriteTypesFileWithTimeout(path, input, 10*time.Second)
+}
+
+// WriteTypesFileWithTimeout is WriteTypesFile waiting at most timeout for
+// other processes writing path. Writers are serialized through an advisory
+// lock on path+".lock", removed once the write is done, and the file is
+// replaced atomically, so it is always either the previous or the new
+// declaration.
+func WriteTypesFileWithTimeout(path string, input map[string]interface{}, timeout time.Duration) (bool, error) {
+	unlock, err := lockFile(path+".lock", timeout)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	content, hash, err := declarationFile(input, typesFileOptions)
+	if err != nil {
+		return false, err
+	}
+	if readTypesFileHash(path) == hash {
+		return false, nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".sst-env-*.tmp")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(declarationHeader("", hash) + content)
+	if err == nil {
+		err = tmp.Chmod(0644)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// readTypesFileHash returns the hash recorded in the header of path, whatever
+// its banner, or an empty string if there is none or the rest of the file no
+// longer matches it.
+func readTypesFileHash(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	header, err := reader.ReadString('\n')
+	if err != nil {
+		return ""
+	}
+	header, ok := strings.CutSuffix(header, " */\n")
+	if !ok || !strings.HasPrefix(header, "/* ") {
+		return ""
+	}
+	index := strings.LastIndex(header, " hash:")
+	if index == -1 {
+		return ""
+	}
+	hash := header[index+len(" hash:"):]
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, reader); err != nil {
+		return ""
+	}
+	if hex.EncodeToString(hasher.Sum(nil)) != hash {
+		return ""
+	}
+	return hash
+}
+
+// lockFile takes the advisory lock on path, polling until timeout, and
+// returns the function releasing it.
+func lockFile(path string, timeout time.Duration) (func(), error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		unlock, err := tryLock(path)
+		if err != nil || unlock != nil {
+			return unlock, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s: %w after %s", path, ErrLockTimeout, timeout)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,50 @@
+//go:build !unix
+
+package project
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// staleLockAge is how long a lock file may go untouched before it is taken
+// to be left behind by a process that died while writing. The holder touches
+// it well within that, however long the write takes.
+const staleLockAge = 5 * time.Second
+
+// tryLock creates path exclusively, as there is no flock to rely on. It
+// returns a nil function when another process holds the lock.
+func tryLock(path string) (func(), error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(path)
+		}
+		return nil, nil
+	}
+	file.Close()
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(staleLockAge / 5)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				os.Chtimes(path, now, now)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+		os.Remove(path)
+	}, nil
+}
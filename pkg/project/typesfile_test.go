@@ -0,0 +1,139 @@
+package project
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var typesFileInput = map[string]interface{}{
+	"MyBucket": map[string]interface{}{
+		"type": "sst.aws.Bucket",
+		"name": "bucket",
+	},
+}
+
+func TestWriteTypesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sst-env.d.ts")
+
+	changed, err := WriteTypesFile(path, typesFileInput)
+	if err != nil || !changed {
+		t.Fatalf("Expected first write, got %v %v", changed, err)
+	}
+	first, _ := os.ReadFile(path)
+	_, hash, _ := declarationFile(typesFileInput, typesFileOptions)
+	if !strings.HasPrefix(string(first), declarationHeader("", hash)) {
+		t.Errorf("Expected hash header, got %v", string(first))
+	}
+	if !strings.Contains(string(first), "  export interface Resource {\n    MyBucket: {\n") {
+		t.Errorf("Expected declaration, got %v", string(first))
+	}
+	if expected, _ := GenerateDeclarationFile(typesFileInput, typesFileOptions); string(first) != expected {
+		t.Errorf("Expected %v, got %v", expected, string(first))
+	}
+
+	changed, err = WriteTypesFile(path, typesFileInput)
+	if err != nil || changed {
+		t.Fatalf("Expected unchanged rewrite to be skipped, got %v %v", changed, err)
+	}
+
+	changed, err = WriteTypesFile(path, map[string]interface{}{"MyQueue": map[string]interface{}{"url": "url"}})
+	if err != nil || !changed {
+		t.Fatalf("Expected changed rewrite, got %v %v", changed, err)
+	}
+	second, _ := os.ReadFile(path)
+	if string(second) == string(first) || !strings.Contains(string(second), "MyQueue") {
+		t.Errorf("Expected new declaration, got %v", string(second))
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("Expected temporary files to be cleaned up, got %v", entries)
+	}
+}
+
+func TestWriteTypesFileWithoutHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sst-env.d.ts")
+	if err := os.WriteFile(path, []byte(`import "sst"`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	changed, err := WriteTypesFile(path, typesFileInput)
+	if err != nil || !changed {
+		t.Fatalf("Expected file without header to be rewritten, got %v %v", changed, err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, []byte(strings.Replace(string(data), "string", "number", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	changed, err = WriteTypesFile(path, typesFileInput)
+	if err != nil || !changed {
+		t.Fatalf("Expected edited file to be rewritten, got %v %v", changed, err)
+	}
+}
+
+func TestWriteTypesFileConcurrently(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sst-env.d.ts")
+	inputs := []map[string]interface{}{
+		typesFileInput,
+		{"MyQueue": map[string]interface{}{"url": "url", "arn": "arn"}},
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*50)
+	for _, input := range inputs {
+		wg.Add(1)
+		go func(input map[string]interface{}) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				if _, err := WriteTypesFile(path, input); err != nil {
+					errs <- err
+				}
+			}
+		}(input)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+	if readTypesFileHash(path) == "" {
+		t.Fatalf("Expected complete declaration")
+	}
+	data, _ := os.ReadFile(path)
+	if _, err := ParseDeclaration(string(data)); err != nil {
+		t.Errorf("Expected parseable declaration, got %v", err)
+	}
+}
+
+func TestWriteTypesFileLockTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sst-env.d.ts")
+	unlock, err := lockFile(path+".lock", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+	if _, err := WriteTypesFileWithTimeout(path, typesFileInput, 50*time.Millisecond); !errors.Is(err, ErrLockTimeout) {
+		t.Errorf("Expected %v, got %v", ErrLockTimeout, err)
+	}
+}
+
+func TestWriteTypesFileStaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sst-env.d.ts")
+	if err := os.WriteFile(path+".lock", nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	stale := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(path+".lock", stale, stale); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := WriteTypesFileWithTimeout(path, typesFileInput, time.Second); err != nil || !changed {
+		t.Fatalf("Expected stale lock to be taken over, got %v %v", changed, err)
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("Expected lock file to be removed, got %v", err)
+	}
+}
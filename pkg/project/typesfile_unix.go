@@ -0,0 +1,44 @@
+//go:build unix
+
+package project
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// tryLock takes an exclusive flock on path without blocking. It returns a
+// nil function when another process holds the lock.
+func tryLock(path string) (func(), error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// The previous holder removes path when unlocking, so the lock only
+	// counts if it is still held on the file at path.
+	locked, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	current, err := os.Stat(path)
+	if err != nil || !os.SameFile(locked, current) {
+		file.Close()
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, nil
+	}
+	return func() {
+		os.Remove(path)
+		file.Close()
+	}, nil
+}
@@ -0,0 +1,133 @@
+package project
+
+import (
+	"errors"
+	"go/format"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// GenerateGo renders input as a Go source file declaring a Resource struct
+// that the linked resource JSON can be unmarshalled into.
+func GenerateGo(input map[string]interface{}, pkgName string) (string, error) {
+	root, inferErr := Infer(input, TypeOptions{Lenient: true})
+	g := &goWriter{}
+	g.object(root.(ObjectType))
+	var builder strings.Builder
+	builder.WriteString("// Code generated by SST. DO NOT EDIT.\n\npackage " + pkgName + "\n\n")
+	if g.raw {
+		builder.WriteString("import \"encoding/json\"\n\n")
+	}
+	builder.WriteString("type Resource " + g.String() + "\n")
+	source, err := format.Source([]byte(builder.String()))
+	if err != nil {
+		return "", errors.Join(inferErr, err)
+	}
+	return string(source), inferErr
+}
+
+type goWriter struct {
+	strings.Builder
+	// raw is set once json.RawMessage is used and has to be imported.
+	raw bool
+}
+
+func (g *goWriter) write(t Type) {
+	switch t := t.(type) {
+	case PrimitiveType:
+		switch t {
+		case "string":
+			g.WriteString("string")
+		case "boolean":
+			g.WriteString("bool")
+		case "number":
+			g.WriteString("float64")
+		case "any":
+			g.WriteString("any")
+		default:
+			g.rawMessage()
+		}
+	case NumberType:
+		if t.Integer {
+			g.WriteString("int64")
+		} else {
+			g.WriteString("float64")
+		}
+	case LiteralType:
+		if _, err := strconv.Unquote(t.Value); err == nil {
+			g.WriteString("string")
+			return
+		}
+		g.rawMessage()
+	case ObjectType:
+		g.object(t)
+	case ArrayType:
+		g.WriteString("[]")
+		g.write(t.Element)
+	case TupleType:
+		g.WriteString("[]")
+		g.rawMessage()
+	case RecordType:
+		g.WriteString("map[string]")
+		g.write(t.Value)
+	case UnionType:
+		// A union is only typed when all of its members map to the same Go
+		// type, like a union of string literals.
+		var member string
+		for i, m := range t.Members {
+			w := &goWriter{}
+			w.write(m)
+			if i > 0 && w.String() != member {
+				g.rawMessage()
+				return
+			}
+			member = w.String()
+			g.raw = g.raw || w.raw
+		}
+		g.WriteString(member)
+	default:
+		g.rawMessage()
+	}
+}
+
+func (g *goWriter) rawMessage() {
+	g.raw = true
+	g.WriteString("json.RawMessage")
+}
+
+func (g *goWriter) object(t ObjectType) {
+	g.WriteString("struct {\n")
+	names := map[string]bool{}
+	for _, field := range t.Fields {
+		name := goFieldName(field.Key)
+		unique := name
+		for i := 2; names[unique]; i++ {
+			unique = name + "_" + strconv.Itoa(i)
+		}
+		names[unique] = true
+		g.WriteString(unique + " ")
+		g.write(field.Type)
+		tag := field.Key
+		if field.Optional {
+			tag += ",omitempty"
+		}
+		tag = "json:" + strconv.Quote(tag)
+		if strings.Contains(tag, "`") {
+			g.WriteString(" " + strconv.Quote(tag) + "\n")
+		} else {
+			g.WriteString(" `" + tag + "`\n")
+		}
+	}
+	g.WriteString("}")
+}
+
+// goFieldName converts key into an exported Go identifier.
+func goFieldName(key string) string {
+	name := strings.TrimPrefix(SanitizeIdentifier(key), "_")
+	if r, _ := utf8.DecodeRuneInString(name); !unicode.IsUpper(r) {
+		name = "X" + name
+	}
+	return name
+}
@@ -0,0 +1,59 @@
+package project
+
+import (
+	"go/format"
+	"testing"
+)
+
+func TestGenerateGo(t *testing.T) {
+	result, err := GenerateGo(map[string]interface{}{
+		"MyBucket": map[string]interface{}{
+			"type": "sst.aws.Bucket",
+			"name": "bucket",
+		},
+		"my-api": map[string]interface{}{
+			"url":    "url",
+			"routes": []interface{}{map[string]interface{}{"path": "/", "port": 80}},
+			"config": Literal("{ port: number }"),
+			"tags":   map[string]string{"env": "dev"},
+			"dlq":    nil,
+		},
+		"MyApi": map[string]interface{}{"public": true},
+		"0":     map[string]interface{}{"value": []interface{}{"a", 1}},
+	}, "resource")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "// Code generated by SST. DO NOT EDIT.\n\n" +
+		"package resource\n\n" +
+		"import \"encoding/json\"\n\n" +
+		"type Resource struct {\n" +
+		"\tX0 struct {\n" +
+		"\t\tValue []json.RawMessage `json:\"value\"`\n" +
+		"\t} `json:\"0\"`\n" +
+		"\tMyApi struct {\n" +
+		"\t\tPublic bool `json:\"public\"`\n" +
+		"\t} `json:\"MyApi\"`\n" +
+		"\tMyBucket struct {\n" +
+		"\t\tName string `json:\"name\"`\n" +
+		"\t\tType string `json:\"type\"`\n" +
+		"\t} `json:\"MyBucket\"`\n" +
+		"\tMyApi_2 struct {\n" +
+		"\t\tConfig json.RawMessage `json:\"config\"`\n" +
+		"\t\tDlq    json.RawMessage `json:\"dlq,omitempty\"`\n" +
+		"\t\tRoutes []struct {\n" +
+		"\t\t\tPath string `json:\"path\"`\n" +
+		"\t\t\tPort int64  `json:\"port\"`\n" +
+		"\t\t} `json:\"routes\"`\n" +
+		"\t\tTags map[string]string `json:\"tags\"`\n" +
+		"\t\tUrl  string            `json:\"url\"`\n" +
+		"\t} `json:\"my-api\"`\n" +
+		"}\n"
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	formatted, err := format.Source([]byte(result))
+	if err != nil || string(formatted) != result {
+		t.Errorf("Expected gofmt-clean output, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// MergeTypes combines two inferred types into one that describes both. Keys
+// present in only one object become optional, nested objects are merged
+// recursively and any other differing types become a union.
+func MergeTypes(a, b Type, opts TypeOptions) (Type, error) {
+	m := &typeMerger{opts: opts}
+	return m.merge(a, b, ""), errors.Join(m.errs...)
+}
+
+type typeMerger struct {
+	opts TypeOptions
+	errs []error
+}
+
+func (m *typeMerger) merge(a, b Type, path string) Type {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a, ok := a.(NumberType); ok {
+		if b, ok := b.(NumberType); ok {
+			return NumberType{Integer: a.Integer && b.Integer}
+		}
+	}
+	if sameType(a, b) {
+		return a
+	}
+	switch a := a.(type) {
+	case ObjectType:
+		if b, ok := b.(ObjectType); ok {
+			return m.object(a, b, path)
+		}
+	case ArrayType:
+		if b, ok := b.(ArrayType); ok {
+			return ArrayType{Element: m.merge(a.Element, b.Element, path+"[]")}
+		}
+	case TupleType:
+		if b, ok := b.(TupleType); ok && len(a.Elements) == len(b.Elements) {
+			result := TupleType{Elements: make([]TupleElement, len(a.Elements))}
+			for i, element := range a.Elements {
+				result.Elements[i] = TupleElement{
+					Type:     m.merge(element.Type, b.Elements[i].Type, path+"["+strconv.Itoa(i)+"]"),
+					Optional: element.Optional || b.Elements[i].Optional,
+				}
+			}
+			return result
+		}
+	case RecordType:
+		if b, ok := b.(RecordType); ok && a.Index == b.Index && sameType(a.Key, b.Key) {
+			return RecordType{Key: a.Key, Value: m.merge(a.Value, b.Value, path+"[]"), Index: a.Index}
+		}
+	}
+	if m.opts.RejectConflicts {
+		m.errs = append(m.errs, fmt.Errorf("%s: conflicting types %s and %s", path, RenderType(a, TypeOptions{}), RenderType(b, TypeOptions{})))
+		return a
+	}
+	var result UnionType
+	for _, t := range []Type{a, b} {
+		members := []Type{t}
+		if u, ok := t.(UnionType); ok {
+			members = u.Members
+		}
+		for _, member := range members {
+			if !containsType(result.Members, member) {
+				result.Members = append(result.Members, member)
+			}
+		}
+	}
+	return result
+}
+
+func (m *typeMerger) object(a, b ObjectType, path string) ObjectType {
+	fields := map[string][]Field{}
+	keys := map[string]interface{}{}
+	for _, object := range []ObjectType{a, b} {
+		for _, field := range object.Fields {
+			fields[field.Key] = append(fields[field.Key], field)
+			keys[field.Key] = nil
+		}
+	}
+	result := ObjectType{Fields: make([]Field, 0, len(keys))}
+	for _, key := range sortedKeys(keys, keyLess(m.opts)) {
+		matches := fields[key]
+		field := matches[0]
+		if len(matches) == 1 {
+			field.Optional = true
+		} else {
+			field.Optional = field.Optional || matches[1].Optional
+			field.Type = m.merge(field.Type, matches[1].Type, joinPath(path, key))
+		}
+		result.Fields = append(result.Fields, field)
+	}
+	return result
+}
+
+func sameType(a, b Type) bool {
+	return RenderType(a, TypeOptions{}) == RenderType(b, TypeOptions{})
+}
+
+func containsType(types []Type, t Type) bool {
+	for _, existing := range types {
+		if sameType(existing, t) {
+			return true
+		}
+	}
+	return false
+}
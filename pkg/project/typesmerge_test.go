@@ -0,0 +1,76 @@
+package project
+
+import (
+	"strings"
+	"testing"
+)
+
+func mergeInputs(t *testing.T, a, b map[string]interface{}, opts TypeOptions) (string, error) {
+	left, err := Infer(a, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	right, err := Infer(b, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, err := MergeTypes(left, right, opts)
+	return RenderType(merged, opts), err
+}
+
+func TestMergeTypesDisjoint(t *testing.T) {
+	result, err := mergeInputs(t,
+		map[string]interface{}{"MyCdn": map[string]interface{}{"url": "url"}},
+		map[string]interface{}{"MyTunnel": map[string]interface{}{"port": 80}},
+		TypeOptions{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{\n  MyCdn?: {\n    url: string\n  }\n  MyTunnel?: {\n    port: number\n  }\n}"
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestMergeTypesIdentical(t *testing.T) {
+	input := map[string]interface{}{
+		"MyBucket": map[string]interface{}{"type": "sst.aws.Bucket", "tags": []interface{}{"a"}},
+	}
+	result, err := mergeInputs(t, input, input, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := InferTypes(input, TypeOptions{})
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestMergeTypesNestedConflict(t *testing.T) {
+	dev := map[string]interface{}{
+		"MyApi": map[string]interface{}{
+			"url":    "url",
+			"config": map[string]interface{}{"port": 3000, "debug": true},
+		},
+	}
+	prod := map[string]interface{}{
+		"MyApi": map[string]interface{}{
+			"url":    "url",
+			"config": map[string]interface{}{"port": "443", "cdn": "cdn"},
+		},
+	}
+	result, err := mergeInputs(t, dev, prod, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{\n  MyApi: {\n    config: {\n      cdn?: string\n      debug?: boolean\n      port: number | string\n    }\n    url: string\n  }\n}"
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	_, err = mergeInputs(t, dev, prod, TypeOptions{RejectConflicts: true})
+	if err == nil || !strings.Contains(err.Error(), "MyApi.config.port: conflicting types number and string") {
+		t.Errorf("Expected conflict error, got %v", err)
+	}
+}
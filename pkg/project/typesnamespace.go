@@ -0,0 +1,84 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// InferNamespaces renders the top-level keys of input split into one
+// namespace per group, each declaring its own Resource interface, and a root
+// interface composing them, so that keys are reached as Resource.aws.MyBucket.
+// When every key falls into the same group the root interface is rendered
+// with the keys directly inside it, as without grouping.
+func InferNamespaces(input map[string]interface{}, opts TypeOptions) (string, error) {
+	root := opts.RootName
+	if root == "" {
+		root = "Resource"
+	}
+	g := &typeGenerator{
+		opts:         opts,
+		discriminate: true,
+	}
+	input = g.augment(input, opts.Augmentations, "")
+	groups := map[string]map[string]interface{}{}
+	for key, value := range input {
+		namespace := namespaceOf(key, opts)
+		if groups[namespace] == nil {
+			groups[namespace] = map[string]interface{}{}
+		}
+		groups[namespace][key] = value
+	}
+	namespaces := make([]string, 0, len(groups))
+	for namespace := range groups {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	var builder strings.Builder
+	w := &typeWriter{w: &builder, opts: opts}
+	if len(namespaces) <= 1 {
+		w.string("export interface " + root + " ")
+		w.object(g.object(input, ""), "")
+	} else {
+		composed := ObjectType{Fields: make([]Field, 0, len(namespaces))}
+		indent := w.indent()
+		for _, namespace := range namespaces {
+			if !isIdentifier(namespace) {
+				g.report(namespace, fmt.Errorf("%s: namespace is not a valid identifier", namespace), false)
+			}
+			w.string("export declare namespace " + namespace + " {\n")
+			w.string(indent + "export interface " + root + " ")
+			w.object(g.object(groups[namespace], ""), indent)
+			w.string("\n}\n\n")
+			composed.Fields = append(composed.Fields, Field{
+				Key:  namespace,
+				Type: ReferenceType{Name: namespace + "." + root},
+			})
+		}
+		w.string("export interface " + root + " ")
+		w.object(composed, "")
+	}
+	g.unusedDescriptions()
+	if opts.TrailingNewline {
+		w.string("\n")
+	}
+	return builder.String(), errors.Join(g.errs...)
+}
+
+func namespaceOf(key string, opts TypeOptions) string {
+	var namespace string
+	if opts.Namespace != nil {
+		namespace = opts.Namespace(key)
+	} else {
+		namespace = opts.Namespaces[key]
+	}
+	if namespace != "" {
+		return namespace
+	}
+	if opts.DefaultNamespace != "" {
+		return opts.DefaultNamespace
+	}
+	return "other"
+}
@@ -0,0 +1,80 @@
+package project
+
+import (
+	"strings"
+	"testing"
+)
+
+var namespaceInput = map[string]interface{}{
+	"MyBucket": map[string]interface{}{"name": "bucket"},
+	"MyQueue":  map[string]interface{}{"url": "url"},
+	"Zone":     map[string]interface{}{"id": "id"},
+	"Stage":    "dev",
+}
+
+func TestInferNamespaces(t *testing.T) {
+	result, err := InferNamespaces(namespaceInput, TypeOptions{
+		Namespaces: map[string]string{
+			"MyBucket": "aws",
+			"MyQueue":  "aws",
+			"Zone":     "cloudflare",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := strings.Join([]string{
+		"export declare namespace aws {",
+		"  export interface Resource {",
+		"    MyBucket: {",
+		"      name: string",
+		"    }",
+		"    MyQueue: {",
+		"      url: string",
+		"    }",
+		"  }",
+		"}",
+		"",
+		"export declare namespace cloudflare {",
+		"  export interface Resource {",
+		"    Zone: {",
+		"      id: string",
+		"    }",
+		"  }",
+		"}",
+		"",
+		"export declare namespace other {",
+		"  export interface Resource {",
+		"    Stage: string",
+		"  }",
+		"}",
+		"",
+		"export interface Resource {",
+		"  aws: aws.Resource",
+		"  cloudflare: cloudflare.Resource",
+		"  other: other.Resource",
+		"}",
+	}, "\n")
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	if _, err := InferNamespaces(namespaceInput, TypeOptions{
+		Namespace: func(key string) string { return "my-" + key },
+	}); err == nil {
+		t.Errorf("Expected invalid namespace error")
+	}
+}
+
+func TestInferNamespacesSingleGroup(t *testing.T) {
+	result, err := InferNamespaces(namespaceInput, TypeOptions{
+		Namespace: func(key string) string { return "aws" },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	flat, _ := InferTypes(namespaceInput, TypeOptions{})
+	if expected := "export interface Resource " + flat; result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
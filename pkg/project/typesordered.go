@@ -0,0 +1,83 @@
+package project
+
+import "reflect"
+
+// OrderedMap is an object whose keys are inferred in insertion order instead
+// of being sorted. It can be nested anywhere a map can.
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func NewOrderedMap() *OrderedMap {
+	return &OrderedMap{values: map[string]interface{}{}}
+}
+
+// Set adds key or replaces its value, keeping its original position.
+func (m *OrderedMap) Set(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *OrderedMap) Get(key string) (interface{}, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+func (m *OrderedMap) Keys() []string {
+	return append([]string(nil), m.keys...)
+}
+
+// InferOrdered builds the type tree for an ordered root object.
+func InferOrdered(input *OrderedMap, opts TypeOptions) (Type, error) {
+	g := &typeGenerator{
+		opts:         opts,
+		discriminate: true,
+	}
+	return g.root(g.ordered(input))
+}
+
+// ordered returns the values of m and records its key order.
+func (g *typeGenerator) ordered(m *OrderedMap) map[string]interface{} {
+	if g.order == nil {
+		g.order = map[uintptr][]string{}
+	}
+	g.order[reflect.ValueOf(m.values).Pointer()] = m.keys
+	return m.values
+}
+
+// keys lists the keys of input in the order of the ordered maps it was
+// merged from. Keys of plain maps are sorted.
+func (g *typeGenerator) keys(input map[string]interface{}, sources []map[string]interface{}) []string {
+	if len(sources) == 0 {
+		sources = []map[string]interface{}{input}
+	}
+	ordered := false
+	for _, source := range sources {
+		_, ok := g.order[reflect.ValueOf(source).Pointer()]
+		ordered = ordered || ok
+	}
+	if g.less == nil {
+		g.less = keyLess(g.opts)
+	}
+	if !ordered {
+		return sortedKeys(input, g.less)
+	}
+	var keys []string
+	seen := map[string]bool{}
+	for _, source := range sources {
+		order, ok := g.order[reflect.ValueOf(source).Pointer()]
+		if !ok {
+			order = sortedKeys(source, g.less)
+		}
+		for _, key := range order {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
@@ -0,0 +1,45 @@
+package project
+
+import "testing"
+
+func TestInferOrdered(t *testing.T) {
+	input := NewOrderedMap()
+	input.Set("MyQueue", map[string]interface{}{"url": "url", "arn": "arn"})
+	input.Set("MyBucket", map[string]interface{}{"name": "bucket"})
+	api := NewOrderedMap()
+	api.Set("url", "url")
+	api.Set("routes", []interface{}{map[string]interface{}{"path": "/", "method": "GET"}})
+	input.Set("MyApi", api)
+	input.Set("MyQueue", map[string]interface{}{"url": "url", "arn": "arn"})
+
+	expected := "{\n" +
+		"  MyQueue: {\n" +
+		"    arn: string\n" +
+		"    url: string\n" +
+		"  }\n" +
+		"  MyBucket: {\n" +
+		"    name: string\n" +
+		"  }\n" +
+		"  MyApi: {\n" +
+		"    url: string\n" +
+		"    routes: {\n" +
+		"      method: string\n" +
+		"      path: string\n" +
+		"    }[]\n" +
+		"  }\n" +
+		"}"
+	for i := 0; i < 2; i++ {
+		result, err := InferOrdered(input, TypeOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rendered := RenderType(result, TypeOptions{}); rendered != expected {
+			t.Errorf("Expected %v, got %v", expected, rendered)
+		}
+	}
+
+	keys := input.Keys()
+	if len(keys) != 3 || keys[0] != "MyQueue" || keys[2] != "MyApi" {
+		t.Errorf("Expected insertion order, got %v", keys)
+	}
+}
@@ -0,0 +1,313 @@
+package project
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseType parses TypeScript as rendered by RenderType back into a type
+// tree. Only the subset of TypeScript the renderer emits is supported;
+// other fragments are kept as literals.
+func ParseType(src string) (Type, error) {
+	p := &typeParser{src: src}
+	t, err := p.union()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos:])
+	}
+	return t, nil
+}
+
+// ParseDeclaration parses the Resource interface out of a declaration file
+// written by WriteTypesFile.
+func ParseDeclaration(src string) (Type, error) {
+	const prefix = "export interface Resource "
+	start := strings.Index(src, prefix)
+	if start == -1 {
+		return nil, fmt.Errorf("no Resource interface")
+	}
+	p := &typeParser{src: src, pos: start + len(prefix)}
+	return p.primary()
+}
+
+type typeParser struct {
+	src string
+	pos int
+}
+
+func (p *typeParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *typeParser) consume(token string) bool {
+	if strings.HasPrefix(p.src[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *typeParser) expect(token string) error {
+	if !p.consume(token) {
+		return p.errorf("expected %q", token)
+	}
+	return nil
+}
+
+func (p *typeParser) skipSpace() {
+	for p.pos < len(p.src) && strings.ContainsRune(" \t\r\n", rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *typeParser) union() (Type, error) {
+	var members []Type
+	for {
+		member, err := p.array()
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+		if !p.consume(" | ") {
+			break
+		}
+	}
+	if len(members) == 1 {
+		return members[0], nil
+	}
+	return UnionType{Members: members}, nil
+}
+
+func (p *typeParser) array() (Type, error) {
+	t, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("[]") {
+		t = ArrayType{Element: t}
+	}
+	return t, nil
+}
+
+var primitiveTypes = map[string]bool{
+	"string": true, "number": true, "boolean": true, "unknown": true, "any": true,
+	"undefined": true, "null": true, "never": true, "object": true, "bigint": true,
+}
+
+func (p *typeParser) primary() (Type, error) {
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of input")
+	}
+	switch {
+	case p.consume("{ [key: "):
+		key, err := p.union()
+		if err == nil {
+			err = p.expect("]: ")
+		}
+		if err != nil {
+			return nil, err
+		}
+		value, err := p.union()
+		if err == nil {
+			err = p.expect(" }")
+		}
+		return RecordType{Key: key, Value: value, Index: true}, err
+	case p.consume("{"):
+		return p.object()
+	case p.consume("("):
+		t, err := p.union()
+		if err == nil {
+			err = p.expect(")")
+		}
+		return t, err
+	case p.consume("["):
+		return p.tuple()
+	case p.consume("Record<"):
+		key, err := p.union()
+		if err == nil {
+			err = p.expect(", ")
+		}
+		if err != nil {
+			return nil, err
+		}
+		value, err := p.union()
+		if err == nil {
+			err = p.expect(">")
+		}
+		return RecordType{Key: key, Value: value}, err
+	case p.src[p.pos] == '"' || p.src[p.pos] == '`':
+		start := p.pos
+		if err := p.skipString(); err != nil {
+			return nil, err
+		}
+		return LiteralType{Value: p.src[start:p.pos], Constant: p.src[start] == '"'}, nil
+	}
+	start := p.pos
+	for p.pos < len(p.src) && (isIdentifierByte(p.src[p.pos]) || p.src[p.pos] == '-' && p.pos == start) {
+		p.pos++
+	}
+	word := p.src[start:p.pos]
+	if word != "" && (p.pos == len(p.src) || strings.ContainsRune(" \n;,)]>|[", rune(p.src[p.pos]))) {
+		switch {
+		case primitiveTypes[word]:
+			return PrimitiveType(word), nil
+		case word == "true" || word == "false" || word[0] == '-' || word[0] >= '0' && word[0] <= '9':
+			return LiteralType{Value: word, Constant: true}, nil
+		}
+		return ReferenceType{Name: word}, nil
+	}
+	p.pos = start
+	return p.raw()
+}
+
+// raw reads a fragment the parser does not understand up to the end of the
+// type it is part of.
+func (p *typeParser) raw() (Type, error) {
+	start := p.pos
+	depth := 0
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '"' || c == '`' || c == '\'':
+			if err := p.skipString(); err != nil {
+				return nil, err
+			}
+			continue
+		case strings.IndexByte("{([<", c) >= 0:
+			depth++
+		case strings.IndexByte("})]>", c) >= 0:
+			if depth == 0 {
+				return LiteralType{Value: p.src[start:p.pos]}, nil
+			}
+			depth--
+		case depth == 0 && (c == '\n' || c == ';' || c == ',' || strings.HasPrefix(p.src[p.pos:], " | ")):
+			return LiteralType{Value: p.src[start:p.pos]}, nil
+		}
+		p.pos++
+	}
+	if start == p.pos {
+		return nil, p.errorf("expected a type")
+	}
+	return LiteralType{Value: p.src[start:p.pos]}, nil
+}
+
+func (p *typeParser) skipString() error {
+	quote := p.src[p.pos]
+	for p.pos++; p.pos < len(p.src); p.pos++ {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos++
+		case quote:
+			p.pos++
+			return nil
+		}
+	}
+	return p.errorf("unterminated string")
+}
+
+func (p *typeParser) tuple() (Type, error) {
+	result := TupleType{Elements: []TupleElement{}}
+	for !p.consume("]") {
+		if len(result.Elements) > 0 {
+			if err := p.expect(", "); err != nil {
+				return nil, err
+			}
+		}
+		t, err := p.union()
+		if err != nil {
+			return nil, err
+		}
+		result.Elements = append(result.Elements, TupleElement{Type: t, Optional: p.consume("?")})
+	}
+	return result, nil
+}
+
+func (p *typeParser) object() (Type, error) {
+	result := ObjectType{Fields: []Field{}}
+	for {
+		p.skipSpace()
+		if p.consume("}") {
+			return result, nil
+		}
+		var field Field
+		if strings.HasPrefix(p.src[p.pos:], "/**") {
+			description, err := p.comment()
+			if err != nil {
+				return nil, err
+			}
+			field.Description = description
+			p.skipSpace()
+		}
+		p.consume("readonly ")
+		key, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		field.Key = key
+		field.Optional = p.consume("?")
+		if err := p.expect(": "); err != nil {
+			return nil, err
+		}
+		if field.Type, err = p.union(); err != nil {
+			return nil, err
+		}
+		if !p.consume(";") {
+			p.consume(",")
+		}
+		if !p.consume("\n") && !strings.HasPrefix(p.src[p.pos:], "}") {
+			return nil, p.errorf("expected a new line")
+		}
+		result.Fields = append(result.Fields, field)
+	}
+}
+
+func (p *typeParser) key() (string, error) {
+	if strings.HasPrefix(p.src[p.pos:], `"`) {
+		start := p.pos
+		if err := p.skipString(); err != nil {
+			return "", err
+		}
+		return strconv.Unquote(p.src[start:p.pos])
+	}
+	start := p.pos
+	for p.pos < len(p.src) && isIdentifierByte(p.src[p.pos]) {
+		p.pos++
+	}
+	if start == p.pos {
+		return "", p.errorf("expected a property name")
+	}
+	return p.src[start:p.pos], nil
+}
+
+// comment parses a JSDoc block written by the renderer into its description.
+func (p *typeParser) comment() (string, error) {
+	end := strings.Index(p.src[p.pos:], "*/")
+	if end == -1 {
+		return "", p.errorf("unterminated comment")
+	}
+	body := p.src[p.pos+3 : p.pos+end]
+	p.pos += end + 2
+	var lines []string
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.TrimPrefix(line, "*"))
+		if line != "" || len(lines) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	description := strings.ReplaceAll(strings.Join(lines, "\n"), `*\/`, "*/")
+	if rest, ok := strings.CutPrefix(description, "@deprecated "); ok {
+		description = "Deprecated: " + rest
+	}
+	return description, nil
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c == '$' || c == '.' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,84 @@
+package project
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTypeRoundTrip(t *testing.T) {
+	input := roundTripInput()
+	delete(input, "Node")
+	input["Docs"] = map[string]interface{}{"name": "docs", "url": Optional("url")}
+	examples := []TypeOptions{
+		{Types: roundTripOptions["roundtrip_default.golden"].Types},
+		{
+			Types:           roundTripOptions["roundtrip_default.golden"].Types,
+			FieldTerminator: ";",
+			TerminateBlocks: true,
+			Indent:          "\t",
+			Descriptions: map[string]string{
+				"Docs.name": "The name",
+				"Docs.url":  "Deprecated: use name\nIt will be removed",
+			},
+		},
+	}
+	for _, opts := range examples {
+		rendered, err := InferTypes(input, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := ParseType(rendered)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again := RenderType(parsed, opts); again != rendered {
+			t.Errorf("Expected %v, got %v", rendered, again)
+		}
+	}
+}
+
+func TestParseDeclaration(t *testing.T) {
+	file, _ := GenerateDeclarationFile(typesFileInput, typesFileOptions)
+	parsed, err := ParseDeclaration(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := Infer(typesFileInput, TypeOptions{})
+	if !reflect.DeepEqual(parsed, expected) {
+		t.Errorf("Expected %v, got %v", expected, parsed)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old, _ := Infer(map[string]interface{}{
+		"MyQueue":  map[string]interface{}{"url": "url", "arn": "arn"},
+		"OldTopic": map[string]interface{}{"arn": "arn"},
+		"MyApi": map[string]interface{}{
+			"routes": []interface{}{map[string]interface{}{"path": "/"}},
+		},
+	}, TypeOptions{})
+	new, _ := Infer(map[string]interface{}{
+		"MyQueue":  map[string]interface{}{"url": 1, "arn": Optional("arn")},
+		"NewTopic": map[string]interface{}{"arn": "arn"},
+		"MyApi": map[string]interface{}{
+			"routes": []interface{}{map[string]interface{}{"path": "/", "method": "GET"}},
+		},
+	}, TypeOptions{})
+	var changes []string
+	for _, change := range Diff(old, new) {
+		changes = append(changes, change.String())
+	}
+	expected := []string{
+		"MyApi.routes[].method added",
+		"MyQueue.arn changed from string to string (optional)",
+		"MyQueue.url changed from string to number",
+		"NewTopic added",
+		"OldTopic removed",
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected %v, got %v", expected, changes)
+	}
+	if changes := Diff(old, old); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+}
@@ -0,0 +1,207 @@
+package project
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GeneratePython renders input as a Python typing stub with a TypedDict for
+// every object. Classes are named the way InferInterfaces names interfaces
+// with HoistNested set.
+func GeneratePython(input map[string]interface{}) (string, error) {
+	g := newInterfaceGenerator(TypeOptions{HoistNested: true})
+	p := &pythonWriter{
+		generator:    g,
+		declarations: g.interfaceDeclarations(input),
+		imports:      map[string]bool{"TypedDict": true},
+	}
+	var classes []string
+	// Writing a class can declare more classes for objects nested in arrays.
+	for i := 0; i < len(p.declarations); i++ {
+		classes = append(classes, p.class(p.declarations[i]))
+	}
+	imports := make([]string, 0, len(p.imports))
+	for name := range p.imports {
+		imports = append(imports, name)
+	}
+	sort.Strings(imports)
+	return "from typing import " + strings.Join(imports, ", ") + "\n\n\n" + strings.Join(classes, "\n\n"), errors.Join(g.errs...)
+}
+
+type pythonWriter struct {
+	generator    *typeGenerator
+	declarations []declaration
+	imports      map[string]bool
+}
+
+func (p *pythonWriter) class(d declaration) string {
+	var builder strings.Builder
+	identifiers := true
+	for _, field := range d.object.Fields {
+		identifiers = identifiers && isPythonIdentifier(field.Key)
+	}
+	if identifiers {
+		builder.WriteString("class " + d.name + "(TypedDict):\n")
+	} else {
+		// Keys that are not identifiers need the functional syntax.
+		builder.WriteString(d.name + " = TypedDict(" + strconv.Quote(d.name) + ", {\n")
+	}
+	for _, field := range d.object.Fields {
+		annotation := p.annotation(field.Type, d.name+SanitizeIdentifier(field.Key))
+		if field.Optional {
+			p.imports["NotRequired"] = true
+			annotation = "NotRequired[" + annotation + "]"
+		}
+		if identifiers {
+			builder.WriteString("    " + field.Key + ": " + annotation + "\n")
+		} else {
+			builder.WriteString("    " + quoteString(field.Key) + ": " + annotation + ",\n")
+		}
+	}
+	if !identifiers {
+		builder.WriteString("})\n")
+	} else if len(d.object.Fields) == 0 {
+		builder.WriteString("    ...\n")
+	}
+	return builder.String()
+}
+
+// annotation renders t as a Python type. Objects are declared as classes
+// called name.
+func (p *pythonWriter) annotation(t Type, name string) string {
+	switch t := t.(type) {
+	case PrimitiveType:
+		switch t {
+		case "string":
+			return "str"
+		case "boolean":
+			return "bool"
+		case "number":
+			return "float"
+		}
+	case NumberType:
+		if t.Integer {
+			return "int"
+		}
+		return "float"
+	case LiteralType:
+		if literals, members, ok := pythonLiterals(t); ok {
+			return p.union(literals, members)
+		}
+	case ReferenceType:
+		return t.Name
+	case ObjectType:
+		name = p.generator.uniqueName(name)
+		p.declarations = append(p.declarations, declaration{name: name, object: t})
+		return name
+	case ArrayType:
+		return "list[" + p.annotation(t.Element, name) + "]"
+	case TupleType:
+		// Optional trailing elements become a union of shorter tuples.
+		var tuples []string
+		elements := make([]string, 0, len(t.Elements))
+		for i, element := range t.Elements {
+			if element.Optional {
+				tuples = append([]string{"tuple[" + strings.Join(elements, ", ") + "]"}, tuples...)
+			}
+			elements = append(elements, p.annotation(element.Type, name+strconv.Itoa(i)))
+		}
+		if len(elements) == 0 {
+			return "tuple[()]"
+		}
+		tuples = append([]string{"tuple[" + strings.Join(elements, ", ") + "]"}, tuples...)
+		return strings.Join(tuples, " | ")
+	case RecordType:
+		return "dict[" + p.annotation(t.Key, name) + ", " + p.annotation(t.Value, name) + "]"
+	case UnionType:
+		// Literal members are folded into a single Literal.
+		var literals, members []string
+		for _, member := range t.Members {
+			if literal, ok := member.(LiteralType); ok {
+				if values, widened, ok := pythonLiterals(literal); ok {
+					literals = append(literals, values...)
+					members = append(members, widened...)
+					continue
+				}
+			}
+			members = append(members, p.annotation(member, name))
+		}
+		return p.union(literals, members)
+	}
+	p.imports["Any"] = true
+	return "Any"
+}
+
+// union renders literals as one Literal ahead of the other members, dropping
+// repeated values.
+func (p *pythonWriter) union(literals, members []string) string {
+	if len(literals) > 0 {
+		p.imports["Literal"] = true
+		members = append([]string{"Literal[" + strings.Join(dedupe(literals), ", ") + "]"}, members...)
+	}
+	return strings.Join(dedupe(members), " | ")
+}
+
+// pythonLiterals splits the values of t, a single literal or a union of them
+// as built by Union, into the ones Literal accepts and float for the numbers
+// it does not. It fails for any other literal.
+func pythonLiterals(t LiteralType) (literals, members []string, ok bool) {
+	for _, value := range strings.Split(t.Value, " | ") {
+		switch value {
+		case "true":
+			literals = append(literals, "True")
+			continue
+		case "false":
+			literals = append(literals, "False")
+			continue
+		}
+		if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+			literals = append(literals, value)
+		} else if _, err := strconv.Unquote(value); err == nil {
+			literals = append(literals, value)
+		} else if _, err := strconv.ParseFloat(value, 64); err == nil {
+			members = append(members, "float")
+		} else {
+			return nil, nil, false
+		}
+	}
+	return literals, members, true
+}
+
+// dedupe drops repeated values, keeping the first of each.
+func dedupe(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := values[:0]
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+var pythonKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true,
+	"assert": true, "async": true, "await": true, "break": true, "class": true,
+	"continue": true, "def": true, "del": true, "elif": true, "else": true,
+	"except": true, "finally": true, "for": true, "from": true, "global": true,
+	"if": true, "import": true, "in": true, "is": true, "lambda": true,
+	"nonlocal": true, "not": true, "or": true, "pass": true, "raise": true,
+	"return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+func isPythonIdentifier(key string) bool {
+	if key == "" || pythonKeywords[key] {
+		return false
+	}
+	for i, r := range key {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package project
+
+import "testing"
+
+func TestGeneratePython(t *testing.T) {
+	result, err := GeneratePython(map[string]interface{}{
+		"MyBucket": map[string]interface{}{
+			"type": "sst.aws.Bucket",
+			"name": "bucket",
+			"size": 10,
+			"tier": Enum(3, 1, 3),
+		},
+		"my-api": map[string]interface{}{
+			"type":   "sst.aws.ApiGatewayV2",
+			"url":    "url",
+			"ratio":  0.5,
+			"dlq":    Optional(map[string]interface{}{"arn": "arn"}),
+			"routes": []interface{}{map[string]interface{}{"path": "/", "auth": nil}},
+			"tags":   map[string]string{"env": "dev"},
+			"stage":  Union([]string{"dev", "production"}),
+			"config": Literal("{ port: number }"),
+		},
+		"MyQueue": map[string]interface{}{
+			"from":     "queue",
+			"auth.key": "key",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectGolden(t, "python.golden", result)
+}
+
+func TestGeneratePythonConst(t *testing.T) {
+	result, err := GeneratePython(map[string]interface{}{
+		"MyApp": map[string]interface{}{
+			"port":    Const(8080),
+			"ratio":   Const(0.5),
+			"enabled": Const(true),
+			"debug":   Const(false),
+			"stage":   Const("production"),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "from typing import Literal, TypedDict\n\n\n" +
+		"class Resource(TypedDict):\n" +
+		"    MyApp: MyApp\n\n\n" +
+		"class MyApp(TypedDict):\n" +
+		"    debug: Literal[False]\n" +
+		"    enabled: Literal[True]\n" +
+		"    port: Literal[8080]\n" +
+		"    ratio: float\n" +
+		"    stage: Literal[\"production\"]\n"
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
@@ -0,0 +1,97 @@
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// GenerateJSONSchema renders input as a JSON Schema describing the same
+// shape InferTypes does.
+func GenerateJSONSchema(input map[string]interface{}) ([]byte, error) {
+	root, err := Infer(input, TypeOptions{})
+	if err != nil {
+		return nil, err
+	}
+	s := &schemaWriter{}
+	schema := s.schema(root, "")
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	if err := errors.Join(s.errs...); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+type schemaWriter struct {
+	errs []error
+}
+
+func (s *schemaWriter) schema(t Type, path string) map[string]interface{} {
+	switch t := t.(type) {
+	case PrimitiveType:
+		switch t {
+		case "string", "number", "boolean":
+			return map[string]interface{}{"type": string(t)}
+		}
+		return map[string]interface{}{}
+	case NumberType:
+		return map[string]interface{}{"type": "number"}
+	case LiteralType:
+		var value interface{}
+		if err := json.Unmarshal([]byte(t.Value), &value); err != nil {
+			s.errs = append(s.errs, fmt.Errorf("%s: literal %s cannot be represented in JSON Schema", path, t.Value))
+			return map[string]interface{}{}
+		}
+		return map[string]interface{}{"const": value}
+	case ObjectType:
+		properties := map[string]interface{}{}
+		required := []string{}
+		for _, field := range t.Fields {
+			properties[field.Key] = s.schema(field.Type, joinPath(path, field.Key))
+			if !field.Optional {
+				required = append(required, field.Key)
+			}
+		}
+		schema := map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+		}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	case ArrayType:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": s.schema(t.Element, path+"[]"),
+		}
+	case TupleType:
+		items := make([]interface{}, len(t.Elements))
+		required := 0
+		for i, element := range t.Elements {
+			items[i] = s.schema(element.Type, path+"["+strconv.Itoa(i)+"]")
+			if !element.Optional {
+				required++
+			}
+		}
+		return map[string]interface{}{
+			"type":        "array",
+			"prefixItems": items,
+			"items":       false,
+			"minItems":    required,
+		}
+	case RecordType:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": s.schema(t.Value, path+"[]"),
+		}
+	case UnionType:
+		members := make([]interface{}, len(t.Members))
+		for i, member := range t.Members {
+			members[i] = s.schema(member, path)
+		}
+		return map[string]interface{}{"anyOf": members}
+	}
+	return map[string]interface{}{}
+}
@@ -0,0 +1,66 @@
+package project
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+var schemaInput = map[string]interface{}{
+	"MyBucket": map[string]interface{}{
+		"type": "sst.aws.Bucket",
+		"name": "bucket",
+		"size": 10,
+	},
+	"MyApi": map[string]interface{}{
+		"url":    "url",
+		"public": Optional(true),
+		"routes": []interface{}{"/", "/users"},
+		"stage":  Literal(`"production"`),
+		"tags":   map[string]string{"env": "dev"},
+	},
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	result, err := GenerateJSONSchema(schemaInput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := GenerateJSONSchema(schemaInput)
+	if string(second) != string(result) {
+		t.Errorf("Expected stable output, got %v and %v", string(result), string(second))
+	}
+	schema, err := jsonschema.CompileString("schema.json", string(result))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var valid interface{}
+	json.Unmarshal([]byte(`{
+		"MyBucket": {"type": "sst.aws.Bucket", "name": "my-bucket", "size": 1},
+		"MyApi": {"url": "https://example.com", "routes": [], "stage": "production", "tags": {}}
+	}`), &valid)
+	if err := schema.Validate(valid); err != nil {
+		t.Errorf("Expected payload to be valid, got %v", err)
+	}
+
+	var invalid interface{}
+	json.Unmarshal([]byte(`{
+		"MyBucket": {"type": "sst.aws.Queue", "name": "my-bucket", "size": 1},
+		"MyApi": {"url": "https://example.com", "routes": [], "stage": "production", "tags": {}}
+	}`), &invalid)
+	if err := schema.Validate(invalid); err == nil {
+		t.Errorf("Expected mismatched discriminator to be invalid")
+	}
+}
+
+func TestGenerateJSONSchemaLiteral(t *testing.T) {
+	_, err := GenerateJSONSchema(map[string]interface{}{
+		"MyApi": map[string]interface{}{"config": Literal("{ port: number }")},
+	})
+	if err == nil || !strings.Contains(err.Error(), "MyApi.config: literal { port: number } cannot be represented") {
+		t.Errorf("Expected literal error, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package project
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// SnapshotOptions configures GenerateFromSnapshot.
+type SnapshotOptions struct {
+	TypeOptions
+	// Transform replaces the value at every dotted key path of the snapshot
+	// before inference, for example to wrap known paths in Literal, Secret
+	// or Optional again. Array elements are reached at path[] as in
+	// Overrides. Objects and arrays are transformed before their contents.
+	Transform func(path string, value interface{}) interface{}
+}
+
+// GenerateFromSnapshot renders the types of the resource payload persisted
+// as JSON in path, as InferTypes renders the payload itself.
+func GenerateFromSnapshot(path string, opts ...SnapshotOptions) (string, error) {
+	var options SnapshotOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("snapshot %s does not exist", path)
+	}
+	if err != nil {
+		return "", err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return "", fmt.Errorf("snapshot %s is not valid JSON: %w", path, err)
+	}
+	input, ok := decoded.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("snapshot %s holds %s instead of an object", path, jsonKind(decoded))
+	}
+	if options.Transform != nil {
+		input = transformSnapshot(input, "", options.Transform)
+	}
+	return InferTypes(input, options.TypeOptions)
+}
+
+func transformSnapshot(input map[string]interface{}, path string, transform func(string, interface{}) interface{}) map[string]interface{} {
+	for key, value := range input {
+		input[key] = transformValue(value, joinPath(path, key), transform)
+	}
+	return input
+}
+
+// transformValue transforms value before descending into it. Array elements
+// are reached at path[], as in Overrides.
+func transformValue(value interface{}, path string, transform func(string, interface{}) interface{}) interface{} {
+	value = transform(path, value)
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return transformSnapshot(v, path, transform)
+	case []interface{}:
+		for i, element := range v {
+			v[i] = transformValue(element, path+"[]", transform)
+		}
+	}
+	return value
+}
+
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case []interface{}:
+		return "an array"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	}
+	return "a number"
+}
@@ -0,0 +1,81 @@
+package project
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateFromSnapshot(t *testing.T) {
+	input := map[string]interface{}{
+		"MyBucket": map[string]interface{}{
+			"type":    "sst.aws.Bucket",
+			"name":    "bucket",
+			"size":    10,
+			"public":  true,
+			"domains": []string{"example.com"},
+		},
+		"MyApi": map[string]interface{}{
+			"url":    "https://example.com",
+			"region": Literal(`"us-east-1" | "eu-west-1"`),
+			"routes": []interface{}{
+				map[string]interface{}{"path": "/", "auth": Literal(`"iam" | "none"`)},
+			},
+		},
+	}
+	expected, err := InferTypes(input, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := GenerateFromSnapshot(path, SnapshotOptions{
+		Transform: func(path string, value interface{}) interface{} {
+			switch path {
+			case "MyApi.region":
+				return Literal(`"us-east-1" | "eu-west-1"`)
+			case "MyApi.routes[].auth":
+				return Literal(`"iam" | "none"`)
+			}
+			return value
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestGenerateFromSnapshotErrors(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"invalid.json": `{"MyBucket": `,
+		"array.json":   `[{"MyBucket": {}}]`,
+	}
+	for file, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := map[string]string{
+		"missing.json": "does not exist",
+		"invalid.json": "is not valid JSON",
+		"array.json":   "holds an array instead of an object",
+	}
+	for file, message := range expected {
+		if _, err := GenerateFromSnapshot(filepath.Join(dir, file)); err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("%s: Expected %v, got %v", file, message, err)
+		}
+	}
+}
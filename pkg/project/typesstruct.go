@@ -0,0 +1,104 @@
+package project
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// structObject converts a struct into the object it marshals to as JSON.
+// Fields tagged omitempty become optional and fields tagged ion:"literal"
+// render as their literal type. Structs reached through the same pointer
+// share one object so cycles between them are detected.
+func (g *typeGenerator) structObject(rv reflect.Value, pointer uintptr) map[string]interface{} {
+	if object, ok := g.structs[pointer]; ok && pointer != 0 {
+		return object
+	}
+	object := map[string]interface{}{}
+	if g.structs == nil {
+		g.structs = map[uintptr]map[string]interface{}{}
+	}
+	if pointer != 0 {
+		g.structs[pointer] = object
+	}
+	g.structFields(rv, object)
+	return object
+}
+
+func (g *typeGenerator) structFields(rv reflect.Value, object map[string]interface{}) {
+	var embedded []reflect.Value
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		tag, hasTag := field.Tag.Lookup("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		value := rv.Field(i)
+		if field.Anonymous && name == "" {
+			for value.Kind() == reflect.Pointer && !value.IsNil() {
+				value = value.Elem()
+			}
+			if value.Kind() == reflect.Struct {
+				// Embedded fields are flattened, losing to fields declared
+				// on the outer struct.
+				embedded = append(embedded, value)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if !hasTag || name == "" {
+			name = field.Name
+		}
+		result := value.Interface()
+		if field.Tag.Get("ion") == "literal" {
+			result = Const(result)
+		}
+		if strings.Contains(","+options+",", ",omitempty,") {
+			result = Optional(result)
+		}
+		object[name] = result
+	}
+	for _, value := range embedded {
+		fields := map[string]interface{}{}
+		g.structFields(value, fields)
+		for key, value := range fields {
+			if _, ok := object[key]; !ok {
+				object[key] = value
+			}
+		}
+	}
+}
+
+// resolve follows non-nil pointers to the value they point to and converts
+// structs into objects. Values with an entry in Types are left alone.
+func (g *typeGenerator) resolve(value interface{}) interface{} {
+	switch value.(type) {
+	case *OrderedMap, optional, dynamic, secret, constant, document, typed:
+		return value
+	}
+	if _, ok := g.opts.Types[reflect.TypeOf(value)]; ok {
+		return value
+	}
+	rv := reflect.ValueOf(value)
+	var pointer uintptr
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		pointer = rv.Pointer()
+		rv = rv.Elem()
+		if _, ok := g.opts.Types[rv.Type()]; ok {
+			return rv.Interface()
+		}
+	}
+	if rv.Kind() != reflect.Struct {
+		if pointer == 0 {
+			return value
+		}
+		return rv.Interface()
+	}
+	if _, ok := rv.Interface().(time.Time); ok {
+		return rv.Interface()
+	}
+	return g.structObject(rv, pointer)
+}
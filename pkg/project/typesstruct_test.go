@@ -0,0 +1,80 @@
+package project
+
+import "testing"
+
+type structBase struct {
+	ID      string `json:"id"`
+	Created string
+}
+
+type structBucket struct {
+	structBase
+	Type     string            `json:"type" ion:"literal"`
+	Name     string            `json:"bucketName"`
+	Size     int64             `json:"size,omitempty"`
+	Secret   string            `json:"-"`
+	Tags     map[string]string `json:"tags"`
+	Rules    []structRule      `json:"rules"`
+	Aliases  []structRule      `json:"aliases"`
+	Parent   *structBucket     `json:"parent,omitempty"`
+	Fallback *structRule       `json:"fallback,omitempty"`
+	internal string
+}
+
+type structRule struct {
+	Path   string `json:"path"`
+	Weight float64
+}
+
+func TestInferStructs(t *testing.T) {
+	bucket := &structBucket{
+		structBase: structBase{ID: "id"},
+		Type:       "sst.aws.Bucket",
+		Tags:       map[string]string{"env": "dev"},
+		Rules:      []structRule{{Path: "/"}},
+	}
+	bucket.Parent = bucket
+	result, err := InferInterfaces(map[string]interface{}{
+		"MyBucket": bucket,
+		"Rules":    map[string]structRule{"root": {}},
+		"Empty":    []structRule{},
+	}, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "export interface Resource {\n" +
+		"  Empty: {\n" +
+		"    Weight: number\n" +
+		"    path: string\n" +
+		"  }[]\n" +
+		"  MyBucket: MyBucket\n" +
+		"  Rules: Record<string, {\n" +
+		"    Weight: number\n" +
+		"    path: string\n" +
+		"  }>\n" +
+		"}\n\n" +
+		"export interface MyBucket {\n" +
+		"  Created: string\n" +
+		"  aliases: {\n" +
+		"    Weight: number\n" +
+		"    path: string\n" +
+		"  }[]\n" +
+		"  bucketName: string\n" +
+		"  fallback?: {\n" +
+		"    Weight: number\n" +
+		"    path: string\n" +
+		"  }\n" +
+		"  id: string\n" +
+		"  parent?: MyBucket\n" +
+		"  rules: {\n" +
+		"    Weight: number\n" +
+		"    path: string\n" +
+		"  }[]\n" +
+		"  size?: number\n" +
+		"  tags: Record<string, string>\n" +
+		"  type: \"sst.aws.Bucket\"\n" +
+		"}"
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
@@ -0,0 +1,205 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValidationError describes a payload value that does not match its shape.
+type ValidationError struct {
+	Path     string
+	Expected string
+	// Actual is the Go type of the value, or "missing".
+	Actual string
+	// Unexpected is set for keys the shape does not declare.
+	Unexpected bool
+}
+
+func (e ValidationError) Error() string {
+	if e.Unexpected {
+		return fmt.Sprintf("%s: unexpected %s", e.Path, e.Actual)
+	}
+	return fmt.Sprintf("%s: expected %s, got %s", e.Path, e.Expected, e.Actual)
+}
+
+// Validate checks payload against a shape built by Infer and returns every
+// mismatch in the order the shape declares its keys.
+func Validate(shape Type, payload map[string]interface{}, opts ...TypeOptions) []ValidationError {
+	v := &validator{}
+	if len(opts) > 0 {
+		v.opts = opts[0]
+	}
+	v.validate(shape, payload, "")
+	return v.errs
+}
+
+type validator struct {
+	opts TypeOptions
+	errs []ValidationError
+}
+
+func (v *validator) validate(t Type, value interface{}, path string) {
+	if !v.matches(t, value, path) {
+		v.errs = append(v.errs, ValidationError{Path: path, Expected: expectedType(t), Actual: fmt.Sprintf("%T", value)})
+	}
+}
+
+// matches reports whether value has type t. Mismatches inside objects,
+// arrays and records are recorded as they are found instead.
+func (v *validator) matches(t Type, value interface{}, path string) bool {
+	rv := reflect.ValueOf(value)
+	switch t := t.(type) {
+	case NumberType:
+		return v.matches(PrimitiveType("number"), value, path)
+	case PrimitiveType:
+		switch t {
+		case "string":
+			return rv.Kind() == reflect.String
+		case "boolean":
+			return rv.Kind() == reflect.Bool
+		case "number":
+			if number, ok := value.(json.Number); ok {
+				_, err := number.Float64()
+				return err == nil
+			}
+			switch rv.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+				reflect.Float32, reflect.Float64:
+				return true
+			}
+			return false
+		}
+		return true
+	case LiteralType:
+		return matchesLiteral(t.Value, value)
+	case ObjectType:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		declared := map[string]bool{}
+		for _, field := range t.Fields {
+			declared[field.Key] = true
+			fieldPath := joinPath(path, field.Key)
+			fieldValue, present := object[field.Key]
+			fieldValue, _ = unwrapOptional(fieldValue)
+			if !present || isNil(fieldValue) {
+				if !field.Optional {
+					v.errs = append(v.errs, ValidationError{Path: fieldPath, Expected: expectedType(field.Type), Actual: "missing"})
+				}
+				continue
+			}
+			if field.Type != nil {
+				v.validate(field.Type, fieldValue, fieldPath)
+			}
+		}
+		if v.opts.ReportUnexpectedKeys {
+			for _, key := range sortedKeys(object, nil) {
+				if !declared[key] {
+					v.errs = append(v.errs, ValidationError{Path: joinPath(path, key), Actual: fmt.Sprintf("%T", object[key]), Unexpected: true})
+				}
+			}
+		}
+		return true
+	case ArrayType:
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return false
+		}
+		for i := 0; i < rv.Len(); i++ {
+			v.validate(t.Element, rv.Index(i).Interface(), path+"["+strconv.Itoa(i)+"]")
+		}
+		return true
+	case TupleType:
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array || rv.Len() > len(t.Elements) {
+			return false
+		}
+		for i, element := range t.Elements {
+			if i >= rv.Len() {
+				if !element.Optional {
+					v.errs = append(v.errs, ValidationError{Path: path + "[" + strconv.Itoa(i) + "]", Expected: expectedType(element.Type), Actual: "missing"})
+				}
+				continue
+			}
+			v.validate(element.Type, rv.Index(i).Interface(), path+"["+strconv.Itoa(i)+"]")
+		}
+		return true
+	case RecordType:
+		if rv.Kind() != reflect.Map {
+			return false
+		}
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, key := range keys {
+			v.validate(t.Value, rv.MapIndex(key).Interface(), joinPath(path, fmt.Sprint(key.Interface())))
+		}
+		return true
+	case UnionType:
+		for _, member := range t.Members {
+			nested := &validator{opts: v.opts}
+			if nested.matches(member, value, path) && len(nested.errs) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// matchesLiteral compares value with JSON literals, or a union of them as
+// built by Union. Other fragments cannot be checked and always match.
+func matchesLiteral(literal string, value interface{}) bool {
+	for _, part := range strings.Split(literal, " | ") {
+		var expected interface{}
+		if err := json.Unmarshal([]byte(part), &expected); err != nil {
+			return true
+		}
+		switch expected := expected.(type) {
+		case string:
+			if rv := reflect.ValueOf(value); rv.Kind() == reflect.String && rv.String() == expected {
+				return true
+			}
+		case float64:
+			if number, ok := toFloat(value); ok && number == expected {
+				return true
+			}
+		case bool:
+			if actual, ok := value.(bool); ok && actual == expected {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	if number, ok := value.(json.Number); ok {
+		f, err := number.Float64()
+		return f, err == nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+func expectedType(t Type) string {
+	if _, ok := t.(ObjectType); ok {
+		return "object"
+	}
+	return RenderType(t, TypeOptions{})
+}
@@ -0,0 +1,60 @@
+package project
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	shape, err := Infer(map[string]interface{}{
+		"MyBucket": map[string]interface{}{
+			"type":   "sst.aws.Bucket",
+			"name":   "bucket",
+			"public": Optional(true),
+		},
+		"MyApi": map[string]interface{}{
+			"url":    "url",
+			"routes": []interface{}{map[string]interface{}{"path": "/", "port": 80}},
+		},
+	}, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := map[string]interface{}{
+		"MyBucket": map[string]interface{}{"type": "sst.aws.Bucket", "name": "my-bucket"},
+		"MyApi": map[string]interface{}{
+			"url":    "https://example.com",
+			"routes": []interface{}{map[string]interface{}{"path": "/users", "port": 443.0}},
+		},
+	}
+	if errs := Validate(shape, valid); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+
+	invalid := map[string]interface{}{
+		"MyBucket": map[string]interface{}{"type": "sst.aws.Queue", "public": "yes", "extra": 1},
+		"MyApi": map[string]interface{}{
+			"url":    "https://example.com",
+			"routes": []interface{}{map[string]interface{}{"path": "/"}, map[string]interface{}{"path": 1, "port": 1}},
+		},
+	}
+	expected := []ValidationError{
+		{Path: "MyApi.routes[0].port", Expected: "number", Actual: "missing"},
+		{Path: "MyApi.routes[1].path", Expected: "string", Actual: "int"},
+		{Path: "MyBucket.name", Expected: "string", Actual: "missing"},
+		{Path: "MyBucket.public", Expected: "boolean", Actual: "string"},
+		{Path: "MyBucket.type", Expected: `"sst.aws.Bucket"`, Actual: "string"},
+		{Path: "MyBucket.extra", Actual: "int", Unexpected: true},
+	}
+	errs := Validate(shape, invalid, TypeOptions{ReportUnexpectedKeys: true})
+	if !reflect.DeepEqual(errs, expected) {
+		t.Errorf("Expected %v, got %v", expected, errs)
+	}
+	if errs[0].Error() != "MyApi.routes[0].port: expected number, got missing" {
+		t.Errorf("Expected error message, got %v", errs[0].Error())
+	}
+	if errs := Validate(shape, invalid); len(errs) != 5 {
+		t.Errorf("Expected unexpected keys to be ignored by default, got %v", errs)
+	}
+}
@@ -0,0 +1,116 @@
+package project
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// GenerateZod renders input as a module exporting ResourceSchema, a Zod
+// schema validating the shape InferTypes describes.
+func GenerateZod(input map[string]interface{}, opts TypeOptions) (string, error) {
+	t, err := Infer(input, opts)
+	return `import { z } from "zod"` + "\n\n" + "export const ResourceSchema = " + RenderZod(t, opts) + "\n", err
+}
+
+// RenderZod renders t as a Zod schema expression, so that a single Infer
+// pass can feed both RenderType and RenderZod.
+func RenderZod(t Type, opts TypeOptions) string {
+	var builder strings.Builder
+	z := &zodWriter{typeWriter{w: &builder, opts: opts}}
+	z.write(t, "")
+	return builder.String()
+}
+
+type zodWriter struct {
+	typeWriter
+}
+
+func (z *zodWriter) write(t Type, indent string) {
+	switch t := t.(type) {
+	case PrimitiveType:
+		switch t {
+		case "string", "number", "boolean", "any", "unknown", "undefined", "null":
+			z.string("z." + string(t) + "()")
+		default:
+			z.string("z.unknown()")
+		}
+	case NumberType:
+		z.string("z.number()")
+	case LiteralType:
+		z.literal(t.Value)
+	case ReferenceType:
+		z.string("z.lazy(() => " + t.Name + "Schema)")
+	case ObjectType:
+		inner := indent + z.indent()
+		z.string("z.object({\n")
+		for _, field := range t.Fields {
+			z.string(inner + propertyKey(field.Key) + ": ")
+			z.write(field.Type, inner)
+			if field.Optional {
+				z.string(".optional()")
+			}
+			z.string(",\n")
+		}
+		z.string(indent + "})")
+	case ArrayType:
+		z.string("z.array(")
+		z.write(t.Element, indent)
+		z.string(")")
+	case TupleType:
+		z.string("z.tuple([")
+		for i, element := range t.Elements {
+			if i > 0 {
+				z.string(", ")
+			}
+			z.write(element.Type, indent)
+			if element.Optional {
+				z.string(".optional()")
+			}
+		}
+		z.string("])")
+	case RecordType:
+		// Object keys are always strings at runtime.
+		z.string("z.record(z.string(), ")
+		z.write(t.Value, indent)
+		z.string(")")
+	case UnionType:
+		z.string("z.union([")
+		for i, member := range t.Members {
+			if i > 0 {
+				z.string(", ")
+			}
+			z.write(member, indent)
+		}
+		z.string("])")
+	default:
+		z.string("z.unknown()")
+	}
+}
+
+// literal renders JSON literals, or a union of them as built by Union.
+// Other TypeScript fragments cannot be checked at runtime.
+func (z *zodWriter) literal(value string) {
+	parts := strings.Split(value, " | ")
+	literals := make([]string, len(parts))
+	for i, part := range parts {
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(part), &parsed); err != nil {
+			z.string("z.unknown()")
+			return
+		}
+		switch parsed.(type) {
+		case nil:
+			literals[i] = "z.null()"
+		case string, float64, bool:
+			literals[i] = "z.literal(" + part + ")"
+		default:
+			z.string("z.unknown()")
+			return
+		}
+	}
+	if len(literals) == 1 {
+		z.string(literals[0])
+		return
+	}
+	z.string("z.union([" + strings.Join(literals, ", ") + "])")
+}
@@ -0,0 +1,25 @@
+package project
+
+import "testing"
+
+func TestGenerateZod(t *testing.T) {
+	result, err := GenerateZod(map[string]interface{}{
+		"MyBucket": map[string]interface{}{
+			"type": "sst.aws.Bucket",
+			"name": "bucket",
+		},
+		"my-api": map[string]interface{}{
+			"url":    "url",
+			"public": Optional(true),
+			"routes": []interface{}{"/", 1},
+			"tags":   Dynamic(map[string]interface{}{"env": "dev"}),
+			"stage":  Union([]string{"dev", "production"}),
+			"config": Literal("{ port: number }"),
+			"dlq":    nil,
+		},
+	}, TypeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectGolden(t, "zod.golden", result)
+}